@@ -0,0 +1,474 @@
+// Package astedit makes targeted edits to Go files that may contain user
+// code. Edits are located with go/ast and spliced into the original source,
+// so everything outside the edited spans, comments included, is kept as
+// written. Every edit is idempotent.
+package astedit
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// File is a parsed Go source file. Each edit updates the source and parses
+// it again, so the AST always matches Source.
+type File struct {
+	path   string
+	module string
+	src    []byte
+	fset   *token.FileSet
+	ast    *ast.File
+}
+
+// Load reads and parses the file at path, in the module of the nearest
+// go.mod above it.
+func Load(path string) (*File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(path, src)
+	if err != nil {
+		return nil, err
+	}
+	f.SetModule(ModulePath(filepath.Dir(path)))
+	return f, nil
+}
+
+// Parse parses src; path is used in error messages and by Save.
+func Parse(path string, src []byte) (*File, error) {
+	f := &File{path: path}
+	if err := f.reparse(src); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SetModule sets the path of the module the file belongs to, which
+// AddImports keeps apart from the standard library.
+func (f *File) SetModule(module string) { f.module = module }
+
+// ModulePath returns the module path declared by the nearest go.mod in dir
+// or above it, or "" if there is none.
+func ModulePath(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			return modfile.ModulePath(data)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func (f *File) reparse(src []byte) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	f.src, f.fset, f.ast = src, fset, file
+	return nil
+}
+
+// Source returns the current source, formatted with gofmt.
+func (f *File) Source() []byte {
+	if formatted, err := format.Source(f.src); err == nil {
+		return formatted
+	}
+	return f.src
+}
+
+// Save writes the formatted source back to the file it was loaded from.
+func (f *File) Save() error {
+	return os.WriteFile(f.path, f.Source(), 0644)
+}
+
+func (f *File) offset(pos token.Pos) int { return f.fset.Position(pos).Offset }
+
+// splice replaces src[start:end] with text and parses the result.
+func (f *File) splice(start, end int, text string) error {
+	var b bytes.Buffer
+	b.Write(f.src[:start])
+	b.WriteString(text)
+	b.Write(f.src[end:])
+	return f.reparse(b.Bytes())
+}
+
+// Func returns the top-level function (not method) with the given name.
+func (f *File) Func(name string) *ast.FuncDecl {
+	for _, decl := range f.ast.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// Calls reports whether the body of function fn calls callee anywhere,
+// e.g. Calls("SetupRoutes", "RegisterUserRoutes") or
+// Calls("SetupRoutes", "docs.Handler").
+func (f *File) Calls(fn, callee string) bool {
+	decl := f.Func(fn)
+	if decl == nil || decl.Body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && calleeName(call.Fun) == callee {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// callStmts returns the statements of body, at any depth, that consist of a
+// call to callee.
+func (f *File) callStmts(body *ast.BlockStmt, callee string) []ast.Stmt {
+	var stmts []ast.Stmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.ExprStmt)
+		if !ok {
+			return true
+		}
+		if call, ok := stmt.X.(*ast.CallExpr); ok && calleeName(call.Fun) == callee {
+			stmts = append(stmts, stmt)
+		}
+		return false
+	})
+	return stmts
+}
+
+// calleeName renders the function of a call, e.g. RegisterUserRoutes or
+// routes.SetupRoutes.
+func calleeName(fun ast.Expr) string {
+	switch t := fun.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if x := calleeName(t.X); x != "" {
+			return x + "." + t.Sel.Name
+		}
+	}
+	return ""
+}
+
+// AppendStmt adds stmt as the last statement of function fn, unless the
+// body already contains a statement with the same code. It reports whether
+// the file changed.
+func (f *File) AppendStmt(fn, stmt string) (bool, error) {
+	decl := f.Func(fn)
+	if decl == nil || decl.Body == nil {
+		return false, fmt.Errorf("%s not found", fn)
+	}
+	want, err := normalizeStmt(stmt)
+	if err != nil {
+		return false, fmt.Errorf("invalid statement %q: %w", stmt, err)
+	}
+	found := false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if s, ok := n.(ast.Stmt); ok && !found && n != decl.Body {
+			if got, err := normalizeStmt(string(f.src[f.offset(s.Pos()):f.offset(s.End())])); err == nil && got == want {
+				found = true
+			}
+		}
+		return !found
+	})
+	if found {
+		return false, nil
+	}
+
+	// Insert on its own line before the closing brace.
+	end := f.offset(decl.Body.Rbrace)
+	start := end
+	for start > 0 && (f.src[start-1] == ' ' || f.src[start-1] == '\t') {
+		start--
+	}
+	prefix := ""
+	if start > 0 && f.src[start-1] != '\n' {
+		prefix = "\n"
+	}
+	return true, f.splice(start, end, prefix+"\t"+strings.TrimSpace(stmt)+"\n")
+}
+
+// normalizeStmt formats a statement so statements that differ only in
+// spacing compare equal.
+func normalizeStmt(stmt string) (string, error) {
+	src, err := format.Source([]byte("package p\n\nfunc _() {\n" + stmt + "\n}\n"))
+	if err != nil {
+		return "", err
+	}
+	body := string(src)
+	body = body[strings.Index(body, "{\n")+2 : strings.LastIndex(body, "}")]
+	return strings.TrimSpace(body), nil
+}
+
+// RemoveCalls deletes the lines of every statement in function fn that
+// calls callee and returns how many were removed.
+func (f *File) RemoveCalls(fn, callee string) (int, error) {
+	decl := f.Func(fn)
+	if decl == nil || decl.Body == nil {
+		return 0, fmt.Errorf("%s not found", fn)
+	}
+	stmts := f.callStmts(decl.Body, callee)
+	// Cut from the end so earlier offsets stay valid.
+	for i := len(stmts) - 1; i >= 0; i-- {
+		start, end := f.offset(stmts[i].Pos()), f.offset(stmts[i].End())
+		for start > 0 && f.src[start-1] != '\n' {
+			start--
+		}
+		for end < len(f.src) && f.src[end] != '\n' {
+			end++
+		}
+		if end < len(f.src) {
+			end++
+		}
+		f.src = append(f.src[:start:start], f.src[end:]...)
+	}
+	if len(stmts) == 0 {
+		return 0, nil
+	}
+	return len(stmts), f.reparse(f.src)
+}
+
+// Methods returns the names of the methods declared on the named type,
+// with either a value or a pointer receiver.
+func (f *File) Methods(recv string) map[string]bool {
+	names := map[string]bool{}
+	for _, decl := range f.ast.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+		typ := fn.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if ident, ok := typ.(*ast.Ident); ok && ident.Name == recv {
+			names[fn.Name.Name] = true
+		}
+	}
+	return names
+}
+
+// AppendDecl adds declarations, such as a function or method, to the end
+// of the file unless the file already declares each of their names. It is
+// an error if the file declares only some of them.
+func (f *File) AppendDecl(code string) (bool, error) {
+	parsed, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\n"+code, parser.SkipObjectResolution)
+	if err != nil {
+		return false, err
+	}
+	have := declNames(f.ast.Decls)
+	var missing, present []string
+	for _, name := range declNames(parsed.Decls) {
+		if slices.Contains(have, name) {
+			present = append(present, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	switch {
+	case len(missing) == 0:
+		return false, nil
+	case len(present) > 0:
+		return false, fmt.Errorf("%s already declares %s", f.path, strings.Join(present, ", "))
+	}
+	trimmed := bytes.TrimRight(f.src, "\n")
+	return true, f.splice(len(trimmed), len(f.src), "\n\n"+strings.TrimSpace(code)+"\n")
+}
+
+// declNames returns the names declared by decls; methods are named
+// Type.Method.
+func declNames(decls []ast.Decl) []string {
+	var names []string
+	for _, decl := range decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name := decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				typ := decl.Recv.List[0].Type
+				if star, ok := typ.(*ast.StarExpr); ok {
+					typ = star.X
+				}
+				if ident, ok := typ.(*ast.Ident); ok {
+					name = ident.Name + "." + name
+				}
+			}
+			names = append(names, name)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, ident := range spec.Names {
+						if ident.Name != "_" {
+							names = append(names, ident.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// Imports returns the file's import specs as written, e.g. `"fmt"` or
+// `ent "example.com/app/ent"`.
+func (f *File) Imports() []string {
+	var specs []string
+	for _, imp := range f.ast.Imports {
+		spec := imp.Path.Value
+		if imp.Name != nil {
+			spec = imp.Name.Name + " " + spec
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// AddImports adds the import specs the file does not import yet. A spec is
+// a quoted path, optionally preceded by a name. Each spec is inserted in
+// sorted position among the imports of its kind, the standard library or
+// the rest, so existing specs keep their order, grouping and comments.
+func (f *File) AddImports(specs ...string) (bool, error) {
+	have := map[string]bool{}
+	for _, imp := range f.ast.Imports {
+		have[imp.Path.Value] = true
+	}
+	added := false
+	for _, spec := range specs {
+		quote := strings.Index(spec, `"`)
+		if quote < 0 {
+			return added, fmt.Errorf("invalid import %q", spec)
+		}
+		if path := spec[quote:]; !have[path] {
+			have[path] = true
+			if err := f.addImport(strings.TrimSpace(spec), path); err != nil {
+				return added, err
+			}
+			added = true
+		}
+	}
+	return added, nil
+}
+
+func (f *File) addImport(spec, path string) error {
+	decl := f.importDecl()
+	if decl == nil {
+		start := f.offset(f.ast.Name.End())
+		return f.splice(start, start, "\n\nimport (\n\t"+spec+"\n)")
+	}
+	if !decl.Lparen.IsValid() {
+		// Turn import "fmt" into a block, keeping any comment after it.
+		start, end := f.offset(decl.Specs[0].Pos()), f.lineEnd(decl.End())
+		if err := f.splice(start, end, "(\n\t"+string(f.src[start:end])+"\n)"); err != nil {
+			return err
+		}
+		decl = f.importDecl()
+	}
+
+	std := f.isStd(path)
+	var same []ast.Spec
+	for _, s := range decl.Specs {
+		if f.isStd(s.(*ast.ImportSpec).Path.Value) == std {
+			same = append(same, s)
+		}
+	}
+	var after ast.Spec
+	for _, s := range same {
+		if s.(*ast.ImportSpec).Path.Value < path {
+			after = s
+		}
+	}
+	switch {
+	case after != nil:
+		end := f.lineEnd(after.End())
+		if end >= f.offset(decl.Rparen) {
+			end = f.offset(decl.Rparen)
+			return f.splice(end, end, "\n\t"+spec+"\n")
+		}
+		return f.splice(end+1, end+1, "\t"+spec+"\n")
+	case len(same) > 0:
+		first := same[0].(*ast.ImportSpec)
+		start := f.lineStart(first.Pos())
+		if first.Doc != nil {
+			start = f.lineStart(first.Doc.Pos())
+		}
+		return f.splice(start, start, "\t"+spec+"\n")
+	case std:
+		// The first standard library import goes in a group of its own
+		// before the others.
+		start := f.offset(decl.Lparen) + 1
+		return f.splice(start, start, "\n\t"+spec+"\n")
+	default:
+		end := f.lineStart(decl.Rparen)
+		return f.splice(end, end, "\n\t"+spec+"\n")
+	}
+}
+
+// importDecl returns the first import block, or the first import
+// declaration if there is no block.
+func (f *File) importDecl() *ast.GenDecl {
+	var first *ast.GenDecl
+	for _, decl := range f.ast.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return gen
+		}
+		if first == nil {
+			first = gen
+		}
+	}
+	return first
+}
+
+// isStd reports whether a quoted import path belongs to the standard
+// library: its first element has no dot and it is not in the file's module.
+func (f *File) isStd(quoted string) bool {
+	path := strings.Trim(quoted, `"`)
+	if f.module != "" && (path == f.module || strings.HasPrefix(path, f.module+"/")) {
+		return false
+	}
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// lineStart returns the offset of the start of the line holding pos.
+func (f *File) lineStart(pos token.Pos) int {
+	i := f.offset(pos)
+	for i > 0 && f.src[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+// lineEnd returns the offset of the newline ending the line holding pos,
+// or the end of the source.
+func (f *File) lineEnd(pos token.Pos) int {
+	i := f.offset(pos)
+	for i < len(f.src) && f.src[i] != '\n' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+package astedit
+
+import "testing"
+
+func TestAddImports(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		specs []string
+		want  string
+	}{
+		{
+			name:  "module package goes with the other imports",
+			src:   "package p\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/gin-gonic/gin\"\n)\n",
+			specs: []string{`"myapp/internal/handlers"`, `"os"`},
+			want:  "package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\n\t\"github.com/gin-gonic/gin\"\n\t\"myapp/internal/handlers\"\n)\n",
+		},
+		{
+			name:  "comments and grouping are kept",
+			src:   "package p\n\nimport (\n\t// for main\n\t\"log\"\n\n\tx \"example.com/x\" // aliased\n\n\t\"myapp/config\"\n)\n",
+			specs: []string{`"fmt"`, `"myapp/internal/routes"`},
+			want:  "package p\n\nimport (\n\t\"fmt\"\n\t// for main\n\t\"log\"\n\n\tx \"example.com/x\" // aliased\n\n\t\"myapp/config\"\n\t\"myapp/internal/routes\"\n)\n",
+		},
+		{
+			name:  "first standard library import",
+			src:   "package p\n\nimport (\n\t\"myapp/config\"\n)\n",
+			specs: []string{`"context"`},
+			want:  "package p\n\nimport (\n\t\"context\"\n\n\t\"myapp/config\"\n)\n",
+		},
+		{
+			name:  "first other import",
+			src:   "package p\n\nimport (\n\t\"fmt\"\n)\n",
+			specs: []string{`ent "myapp/ent"`},
+			want:  "package p\n\nimport (\n\t\"fmt\"\n\n\tent \"myapp/ent\"\n)\n",
+		},
+		{
+			name:  "single import becomes a block",
+			src:   "package p\n\nimport \"fmt\" // printing\n\nvar _ = fmt.Sprint\n",
+			specs: []string{`"errors"`},
+			want:  "package p\n\nimport (\n\t\"errors\"\n\t\"fmt\" // printing\n)\n\nvar _ = fmt.Sprint\n",
+		},
+		{
+			name:  "no imports yet",
+			src:   "package p\n\nvar x = 1\n",
+			specs: []string{`"myapp/config"`},
+			want:  "package p\n\nimport (\n\t\"myapp/config\"\n)\n\nvar x = 1\n",
+		},
+		{
+			name:  "already imported",
+			src:   "package p\n\nimport (\n\tc \"myapp/config\"\n)\n",
+			specs: []string{`"myapp/config"`},
+			want:  "package p\n\nimport (\n\tc \"myapp/config\"\n)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse("p.go", []byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.SetModule("myapp")
+			changed, err := f.AddImports(tt.specs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(f.Source()); got != tt.want {
+				t.Errorf("AddImports() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if changed != (tt.src != tt.want) {
+				t.Errorf("AddImports() changed = %v", changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/astedit"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/regions"
+	"github.com/samznd/goscaf/pkg/utils"
+)
+
+// changeSet stages file writes and Go source edits to a project so they can
+// be shown with --dry-run, or applied all or nothing: every change is
+// computed before anything is written, and if a write fails the files
+// written so far are restored.
+type changeSet struct {
+	projectPath string
+	m           *manifest.Manifest
+	changes     []change
+}
+
+// change is a file to write, or an edit to a Go file when edit is set.
+type change struct {
+	rel       string
+	content   string
+	overwrite bool
+	edit      func(*astedit.File) (bool, error)
+}
+
+// fileChange is the outcome of a change set for one file. before is nil
+// for a file that does not exist yet.
+type fileChange struct {
+	rel           string
+	before, after *string
+	edited        bool
+}
+
+func newChangeSet(projectPath string, m *manifest.Manifest) *changeSet {
+	return &changeSet{projectPath: projectPath, m: m}
+}
+
+// write stages a file. Go files are formatted and keep the custom regions
+// of the file they replace. An existing file is only replaced if overwrite
+// is set, unless it already has the content.
+func (c *changeSet) write(rel, content string, overwrite bool) {
+	c.changes = append(c.changes, change{rel: filepath.ToSlash(rel), content: content, overwrite: overwrite})
+}
+
+// editGo stages an edit to a Go file; edit reports whether it changed
+// anything. Like editTracked, the file stays tracked in the manifest only if
+// the user had not edited it.
+func (c *changeSet) editGo(rel string, edit func(*astedit.File) (bool, error)) {
+	c.changes = append(c.changes, change{rel: filepath.ToSlash(rel), edit: edit})
+}
+
+// plan computes the content of every file the change set touches, in the
+// order the files are first touched, without writing anything.
+func (c *changeSet) plan() ([]*fileChange, error) {
+	var files []*fileChange
+	byPath := map[string]*fileChange{}
+	for _, ch := range c.changes {
+		if err := checkRelPath(ch.rel); err != nil {
+			return nil, err
+		}
+		fc, ok := byPath[ch.rel]
+		if !ok {
+			fc = &fileChange{rel: ch.rel}
+			data, err := os.ReadFile(filepath.Join(c.projectPath, filepath.FromSlash(ch.rel)))
+			if err == nil {
+				before := string(data)
+				fc.before, fc.after = &before, &before
+			} else if !os.IsNotExist(err) {
+				return nil, err
+			}
+			byPath[ch.rel] = fc
+			files = append(files, fc)
+		}
+
+		if ch.edit != nil {
+			if fc.after == nil {
+				return nil, fmt.Errorf("cannot edit %s: no such file", ch.rel)
+			}
+			f, err := astedit.Parse(ch.rel, []byte(*fc.after))
+			if err != nil {
+				return nil, err
+			}
+			f.SetModule(astedit.ModulePath(c.projectPath))
+			changed, err := ch.edit(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ch.rel, err)
+			}
+			if changed {
+				after := string(f.Source())
+				fc.after, fc.edited = &after, true
+			}
+			continue
+		}
+
+		content := ch.content
+		if strings.HasSuffix(ch.rel, ".go") {
+			if src, err := format.Source([]byte(content)); err == nil {
+				content = string(src)
+			}
+		}
+		if fc.after != nil && !ch.overwrite {
+			if *fc.after == content {
+				continue
+			}
+			return nil, fmt.Errorf("%s already exists", ch.rel)
+		}
+		if strings.HasSuffix(ch.rel, ".go") {
+			if fc.after != nil {
+				restored, lost := regions.Restore(content, *fc.after)
+				if len(lost) > 0 {
+					return nil, fmt.Errorf("%s: %w: %s", ch.rel, errRegionsLost, strings.Join(lost, ", "))
+				}
+				content = restored
+			}
+		}
+		fc.after, fc.edited = &content, false
+	}
+	return files, nil
+}
+
+// checkRelPath rejects paths that are absolute or leave the project.
+func checkRelPath(rel string) error {
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if rel == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid path %q: must be relative to the project root", rel)
+	}
+	return nil
+}
+
+// preview prints what apply would do.
+func (c *changeSet) preview() error {
+	files, err := c.plan()
+	if err != nil {
+		return err
+	}
+	for _, fc := range files {
+		switch {
+		case fc.before == nil:
+			fmt.Printf("✅ Would create %s\n", fc.rel)
+		case *fc.before != *fc.after:
+			fmt.Printf("✅ Would update %s\n", fc.rel)
+		}
+	}
+	return nil
+}
+
+// apply writes the change set and, if there is a manifest, tracks the files
+// goscaf now owns in it; saving it is up to the caller. If a write fails,
+// every file written before it is restored and the error is returned.
+func (c *changeSet) apply() error {
+	files, err := c.plan()
+	if err != nil {
+		return err
+	}
+
+	var done []*fileChange
+	var dirs []string
+	rollback := func(cause error) error {
+		var errs []error
+		for i := len(done) - 1; i >= 0; i-- {
+			fc := done[i]
+			path := filepath.Join(c.projectPath, filepath.FromSlash(fc.rel))
+			if fc.before == nil {
+				errs = append(errs, os.Remove(path))
+			} else {
+				errs = append(errs, os.WriteFile(path, []byte(*fc.before), 0644))
+			}
+		}
+		for i := len(dirs) - 1; i >= 0; i-- {
+			os.Remove(dirs[i]) // only if still empty
+		}
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("%w; rolling back also failed: %v", cause, err)
+		}
+		fmt.Printf("⚠️  Rolled back %d file(s)\n", len(done))
+		return cause
+	}
+
+	var track []string
+	for _, fc := range files {
+		if fc.before != nil && *fc.before == *fc.after {
+			continue
+		}
+		path := filepath.Join(c.projectPath, filepath.FromSlash(fc.rel))
+		unmodified := c.m != nil && !c.m.Modified(c.projectPath, fc.rel)
+		created, err := mkdirAll(filepath.Dir(path))
+		dirs = append(dirs, created...)
+		if err != nil {
+			return rollback(err)
+		}
+		if fc.before == nil {
+			err = utils.CreateFile(path, *fc.after)
+		} else {
+			err = os.WriteFile(path, []byte(*fc.after), 0644)
+			if err == nil {
+				fmt.Println("✅ Updated file:", path)
+			}
+		}
+		if err != nil {
+			return rollback(err)
+		}
+		done = append(done, fc)
+		if fc.before == nil || !fc.edited || unmodified {
+			track = append(track, path)
+		}
+	}
+	if c.m == nil {
+		return nil
+	}
+	return c.m.Track(c.projectPath, track...)
+}
+
+// mkdirAll is os.MkdirAll that also returns the directories it created,
+// outermost first.
+func mkdirAll(dir string) ([]string, error) {
+	var created []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil || d == filepath.Dir(d) {
+			break
+		}
+		created = append([]string{d}, created...)
+	}
+	return created, os.MkdirAll(dir, 0755)
+}
@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+)
+
+var CheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report how a project has drifted from " + manifest.FileName,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		m, err := manifest.Load(projectPath)
+		if err != nil {
+			err = fmt.Errorf("could not read %s: %w", manifest.FileName, err)
+			if asJSON {
+				json.NewEncoder(os.Stdout).Encode(map[string]string{"error": err.Error()})
+			} else {
+				fmt.Printf("❌ Error: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		report := checkProject(projectPath, m)
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(report)
+		} else {
+			report.print()
+		}
+		if !report.Clean {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	CheckCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	CheckCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
+
+// driftReport is the result of goscaf check. Clean is false if any file,
+// dependency or .env key drifted; extra .env keys and an older goscaf
+// version are reported but do not count as drift.
+type driftReport struct {
+	Clean          bool        `json:"clean"`
+	ProjectVersion string      `json:"project_version"`
+	GoscafVersion  string      `json:"goscaf_version"`
+	Files          []fileDrift `json:"files"`
+	Dependencies   []depDrift  `json:"dependencies"`
+	Env            []envDrift  `json:"env"`
+}
+
+// fileDrift is a tracked file that was modified, deleted or renamed.
+type fileDrift struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	To     string `json:"to,omitempty"`
+}
+
+// depDrift is a module the project's stack needs that go.mod lacks, or a
+// go.mod that does not declare the recorded module.
+type depDrift struct {
+	Module string `json:"module"`
+	Status string `json:"status"`
+	Found  string `json:"found,omitempty"`
+}
+
+// envDrift is a .env key that goscaf generated and the file lacks, or one
+// it did not generate.
+type envDrift struct {
+	Key    string `json:"key"`
+	Status string `json:"status"`
+}
+
+// checkProject compares the project on disk with the manifest without
+// changing anything.
+func checkProject(projectPath string, m *manifest.Manifest) driftReport {
+	r := driftReport{
+		ProjectVersion: m.Version,
+		GoscafVersion:  Version,
+		Files:          []fileDrift{},
+		Dependencies:   []depDrift{},
+		Env:            []envDrift{},
+	}
+
+	// Files: a deleted file whose content now lives under an untracked path
+	// was renamed.
+	var deleted []string
+	for _, rel := range m.Paths() {
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(rel))); os.IsNotExist(err) {
+			deleted = append(deleted, rel)
+		} else if m.Modified(projectPath, rel) {
+			r.Files = append(r.Files, fileDrift{Path: rel, Status: "modified"})
+		}
+	}
+	untracked := map[string]string{}
+	if len(deleted) > 0 {
+		untracked = untrackedFiles(projectPath, m)
+	}
+	for _, rel := range deleted {
+		if to, ok := untracked[m.Files[rel]]; ok {
+			r.Files = append(r.Files, fileDrift{Path: rel, Status: "renamed", To: to})
+		} else {
+			r.Files = append(r.Files, fileDrift{Path: rel, Status: "deleted"})
+		}
+	}
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+
+	// Dependencies
+	data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	var mod *modfile.File
+	if err == nil {
+		mod, err = modfile.ParseLax("go.mod", data, nil)
+	}
+	if err != nil {
+		r.Dependencies = append(r.Dependencies, depDrift{Module: m.Module, Status: "no go.mod"})
+	} else {
+		if mod.Module != nil && mod.Module.Mod.Path != m.Module {
+			r.Dependencies = append(r.Dependencies, depDrift{Module: m.Module, Status: "renamed", Found: mod.Module.Mod.Path})
+		}
+		driver := m.SQLiteDriver
+		if driver == "" {
+			driver = "modernc"
+		}
+		modules, _ := stackDependencies(m.Framework, m.Database, m.ORM, driver)
+		modules = append(modules, rpcModules(strings.ToLower(m.RPC))...)
+		for _, module := range modules {
+			module, _, _ = strings.Cut(module, "@")
+			if !requires(mod, module) {
+				r.Dependencies = append(r.Dependencies, depDrift{Module: module, Status: "missing"})
+			}
+		}
+	}
+
+	// .env
+	var want []string
+	if m.Database != "" {
+		want = envKeys(getEnvFile(m.Database))
+	}
+	have := map[string]bool{}
+	if data, err := os.ReadFile(filepath.Join(projectPath, ".env")); err == nil {
+		for _, key := range envKeys(string(data)) {
+			have[key] = true
+		}
+	}
+	for _, key := range want {
+		if !have[key] {
+			r.Env = append(r.Env, envDrift{Key: key, Status: "missing"})
+		}
+		delete(have, key)
+	}
+	var extra []string
+	for key := range have {
+		extra = append(extra, key)
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		r.Env = append(r.Env, envDrift{Key: key, Status: "extra"})
+	}
+
+	r.Clean = len(r.Files) == 0 && len(r.Dependencies) == 0
+	for _, e := range r.Env {
+		if e.Status != "extra" {
+			r.Clean = false
+		}
+	}
+	return r
+}
+
+func (r driftReport) print() {
+	for _, f := range r.Files {
+		switch f.Status {
+		case "renamed":
+			fmt.Printf("⚠️  %s was renamed to %s\n", f.Path, f.To)
+		default:
+			fmt.Printf("⚠️  %s was %s since goscaf generated it\n", f.Path, f.Status)
+		}
+	}
+	for _, d := range r.Dependencies {
+		switch d.Status {
+		case "no go.mod":
+			fmt.Println("⚠️  go.mod is missing or invalid; run `go mod init " + d.Module + "`")
+		case "renamed":
+			fmt.Printf("⚠️  go.mod declares module %s, but the project was generated as %s\n", d.Found, d.Module)
+		default:
+			fmt.Printf("⚠️  go.mod does not require %s; run `go get %s`\n", d.Module, d.Module)
+		}
+	}
+	for _, e := range r.Env {
+		if e.Status == "missing" {
+			fmt.Printf("⚠️  .env does not set %s\n", e.Key)
+		} else {
+			fmt.Printf("ℹ️  .env sets %s, which goscaf did not generate\n", e.Key)
+		}
+	}
+	if r.ProjectVersion != "" && r.ProjectVersion != r.GoscafVersion {
+		fmt.Printf("ℹ️  Generated by goscaf %s; run `goscaf upgrade` to apply the templates of %s\n", r.ProjectVersion, r.GoscafVersion)
+	}
+	if r.Clean {
+		fmt.Println("✅ No drift from " + manifest.FileName)
+	}
+}
+
+// requires reports whether go.mod requires the module providing path.
+func requires(mod *modfile.File, path string) bool {
+	for _, req := range mod.Require {
+		if p := req.Mod.Path; path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// untrackedFiles hashes the project's files the manifest does not track,
+// skipping hidden and vendored directories, and returns their paths by hash.
+func untrackedFiles(projectPath string, m *manifest.Manifest) map[string]string {
+	files := map[string]string{}
+	filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != projectPath && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		rel = filepath.ToSlash(rel)
+		if _, tracked := m.Files[rel]; tracked {
+			return nil
+		}
+		if sum, err := manifest.Hash(path); err == nil {
+			files[sum] = rel
+		}
+		return nil
+	})
+	return files
+}
+
+// envKeys returns the keys set in the contents of a .env file, in order.
+func envKeys(content string) []string {
+	var keys []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, _, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if ok {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+	return keys
+}
@@ -5,7 +5,13 @@ import (
 	"strings"
 )
 
-func getMainFile(backend string, projectName string) string {
+func getMainFile(backend string, projectName string, migrate bool, repoArg string) string {
+	migrateCall := ""
+	if migrate {
+		migrateCall = "\n    config.Migrate()"
+	}
+	wiring := fmt.Sprintf("h := handlers.NewHandler(services.NewService(repositories.NewRepository(%s)))", repoArg)
+
 	switch strings.ToLower(backend) {
 	case "fiber":
 		return fmt.Sprintf(`package main
@@ -13,73 +19,78 @@ func getMainFile(backend string, projectName string) string {
 import (
     "log"
     "github.com/gofiber/fiber/v3"
-    "%s/config"
-    "%s/pkg/utils"
-    "%s/internal/routes"
-    "%s/internal/handlers"
+    "%[1]s/config"
+    "%[1]s/pkg/utils"
+    "%[1]s/internal/routes"
+    "%[1]s/internal/handlers"
+    "%[1]s/internal/repositories"
+    "%[1]s/internal/services"
 )
 
 func main() {
     utils.InitialEnv()
-    config.Connect()
+    config.Connect()%[2]s
     app := fiber.New()
 
     // Define routes
-	api := app.Group("/api/v1")
-	routes.SetupRoutes(api, &handlers.Handler{})
+    %[3]s
+    routes.SetupRoutes(app, h)
 
     log.Println("🚀 Fiber server is running on http://localhost:3000")
     app.Listen(":3000")
-}`, projectName, projectName, projectName, projectName)
+}`, projectName, migrateCall, wiring)
 	case "gin":
 		return fmt.Sprintf(`package main
 
 import (
     "log"
     "github.com/gin-gonic/gin"
-    "%s/config"
-    "%s/pkg/utils"
-    "%s/internal/routes"
-    "%s/internal/handlers"
+    "%[1]s/config"
+    "%[1]s/pkg/utils"
+    "%[1]s/internal/routes"
+    "%[1]s/internal/handlers"
+    "%[1]s/internal/repositories"
+    "%[1]s/internal/services"
 )
 
 func main() {
     utils.InitialEnv()
-    config.Connect()
+    config.Connect()%[2]s
 
     r := gin.Default()
 
     // Define routes
-	api := r.Group("/api/v1")
-	routes.SetupRoutes(api, &handlers.Handler{})
+    %[3]s
+    routes.SetupRoutes(r, h)
 
     log.Println("🚀 Gin server is running on http://localhost:3000")
     r.Run(":3000")
-}`, projectName, projectName, projectName, projectName)
+}`, projectName, migrateCall, wiring)
 	case "echo":
 		return fmt.Sprintf(`package main
 
 import (
-    "net/http"
     "github.com/labstack/echo/v4"
-    "%s/config"
-    "%s/pkg/utils"
-    "%s/internal/routes"
-    "%s/internal/handlers"
+    "%[1]s/config"
+    "%[1]s/pkg/utils"
+    "%[1]s/internal/routes"
+    "%[1]s/internal/handlers"
+    "%[1]s/internal/repositories"
+    "%[1]s/internal/services"
 )
 
 func main() {
     utils.InitialEnv()
-    config.Connect()
+    config.Connect()%[2]s
 
     e := echo.New()
 
     // Define routes
-	api := e.Group("/api/v1")
-	routes.SetupRoutes(api, &handlers.Handler{})
+    %[3]s
+    routes.SetupRoutes(e, h)
 
     e.Logger.Fatal(e.Start(":3000"))
-}`, projectName, projectName, projectName, projectName)
+}`, projectName, migrateCall, wiring)
 	case "chi":
 		return fmt.Sprintf(`package main
 
@@ -87,54 +98,60 @@ import (
     "net/http"
     "github.com/go-chi/chi/v5"
     "github.com/go-chi/chi/v5/middleware"
-    "%s/config"
-    "%s/pkg/utils"
-    "%s/internal/routes"
-    "%s/internal/handlers"
+    "%[1]s/config"
+    "%[1]s/pkg/utils"
+    "%[1]s/internal/routes"
+    "%[1]s/internal/handlers"
+    "%[1]s/internal/repositories"
+    "%[1]s/internal/services"
 )
 
 func main() {
     utils.InitialEnv()
-    config.Connect()
+    config.Connect()%[2]s
 
     r := chi.NewRouter()
     r.Use(middleware.Logger)
 
     // Define routes
-    routes.SetupRoutes(r, &handlers.Handler{})
+    %[3]s
+    routes.SetupRoutes(r, h)
 
     http.ListenAndServe(":3000", r)
-}`, projectName, projectName, projectName, projectName)
+}`, projectName, migrateCall, wiring)
 	case "iris":
 		return fmt.Sprintf(`package main
 
 import (
     "github.com/kataras/iris/v12"
-    "%s/config"
-    "%s/pkg/utils"
-    "%s/internal/routes"
-    "%s/internal/handlers"
+    "%[1]s/config"
+    "%[1]s/pkg/utils"
+    "%[1]s/internal/routes"
+    "%[1]s/internal/handlers"
+    "%[1]s/internal/repositories"
+    "%[1]s/internal/services"
 )
 
 func main() {
     utils.InitialEnv()
-    config.Connect()
+    config.Connect()%[2]s
 
     app := iris.New()
 
     // Define routes
-    routes.SetupRoutes(&app, &handlers.Handler{})
+    %[3]s
+    routes.SetupRoutes(app, h)
 
     app.Listen(":3000")
-}`, projectName, projectName, projectName, projectName)
+}`, projectName, migrateCall, wiring)
 	default:
 		return ""
 	}
 }
 
-func getDatabaseFile(database string, orm string) string {
+func getDatabaseFile(database string, orm string, sqliteDriver string, projectName string) string {
 	if strings.ToLower(orm) != "none" {
-		return SetupORM(orm, database)
+		return SetupORM(orm, database, sqliteDriver, projectName)
 	}
 
 	template := ""
@@ -212,6 +229,36 @@ func Connect() {
     log.Println("✅ Connected to the database successfully!")
 }`
 	case "sqlite":
+		if sqliteDriver == "modernc" {
+			template = `package config
+
+import (
+    "database/sql"
+    "log"
+    "os"
+    _ "modernc.org/sqlite"
+)
+
+var DB *sql.DB
+
+func Connect() {
+    dbName := os.Getenv("DB_NAME")
+
+    var err error
+    DB, err = sql.Open("sqlite", dbName)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    err = DB.Ping()
+    if err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    log.Println("✅ Connected to the database successfully!")
+}`
+			break
+		}
 		template = `package config
 
 import (
@@ -238,6 +285,115 @@ func Connect() {
     }
 
     log.Println("✅ Connected to the database successfully!")
+}`
+	case "sqlserver":
+		template = `package config
+
+import (
+    "database/sql"
+    "fmt"
+    "log"
+    "os"
+    _ "github.com/microsoft/go-mssqldb"
+)
+
+var DB *sql.DB
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    var err error
+    DB, err = sql.Open("sqlserver", dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    err = DB.Ping()
+    if err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    log.Println("✅ Connected to the database successfully!")
+}`
+	case "cockroachdb":
+		template = `package config
+
+import (
+    "database/sql"
+    "fmt"
+    "log"
+    "os"
+    _ "github.com/lib/pq"
+)
+
+var DB *sql.DB
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    var err error
+    DB, err = sql.Open("postgres", dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    err = DB.Ping()
+    if err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    log.Println("✅ Connected to the database successfully!")
+}`
+	case "mongodb":
+		template = `package config
+
+import (
+    "context"
+    "log"
+    "os"
+    "time"
+
+    "go.mongodb.org/mongo-driver/v2/mongo"
+    "go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var Client *mongo.Client
+var DB *mongo.Database
+
+func Connect() {
+    uri := os.Getenv("MONGO_URI")
+    dbName := os.Getenv("MONGO_DB")
+
+    var err error
+    Client, err = mongo.Connect(options.Client().ApplyURI(uri))
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    if err := Client.Ping(ctx, nil); err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    DB = Client.Database(dbName)
+
+    log.Println("✅ Connected to the MongoDB database successfully!")
 }`
 	default:
 		fmt.Printf("Warning: Unknown database type '%s'\n", database)
@@ -247,10 +403,15 @@ func Connect() {
 	return template
 }
 
-func SetupORM(orm string, database string) string {
+func SetupORM(orm string, database string, sqliteDriver string, projectName string) string {
 	ormLower := strings.ToLower(orm)
 	dbLower := strings.ToLower(database)
 
+	// sqlc generates code on top of a plain database/sql connection.
+	if ormLower == "sqlc" {
+		return getDatabaseFile(database, "none", sqliteDriver, projectName)
+	}
+
 	if ormLower == "gorm" {
 		switch dbLower {
 		case "postgres":
@@ -314,6 +475,30 @@ func Connect() {
     log.Println("✅ Connected to the MySQL database successfully!")
 }`
 		case "sqlite":
+			if sqliteDriver == "modernc" {
+				return `package config
+
+import (
+    "github.com/glebarez/sqlite"
+    "gorm.io/gorm"
+    "log"
+    "os"
+)
+
+var DB *gorm.DB
+
+func Connect() {
+    dbName := os.Getenv("DB_NAME")
+
+    var err error
+    DB, err = gorm.Open(sqlite.Open(dbName), &gorm.Config{})
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    log.Println("✅ Connected to the SQLite database successfully!")
+}`
+			}
 			return `package config
 
 import (
@@ -326,7 +511,7 @@ import (
 var DB *gorm.DB
 
 func Connect() {
-    dbName := os.Getenv("DB_NAME", "mydb.db")
+    dbName := os.Getenv("DB_NAME")
 
     var err error
     DB, err = gorm.Open(sqlite.Open(dbName), &gorm.Config{})
@@ -335,6 +520,68 @@ func Connect() {
     }
 
     log.Println("✅ Connected to the SQLite database successfully!")
+}`
+		case "sqlserver":
+			return `package config
+
+import (
+    "fmt"
+    "gorm.io/driver/sqlserver"
+    "gorm.io/gorm"
+    "log"
+    "os"
+)
+
+var DB *gorm.DB
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    var err error
+    DB, err = gorm.Open(sqlserver.Open(dsn), &gorm.Config{})
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    log.Println("✅ Connected to the SQL Server database successfully!")
+}`
+		case "cockroachdb":
+			return `package config
+
+import (
+    "fmt"
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "log"
+    "os"
+)
+
+var DB *gorm.DB
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    var err error
+    DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    log.Println("✅ Connected to the CockroachDB database successfully!")
 }`
 		}
 	} else if ormLower == "xorm" {
@@ -410,6 +657,34 @@ func Connect() {
     log.Println("✅ Connected to the MySQL database successfully!")
 }`
 		case "sqlite":
+			if sqliteDriver == "modernc" {
+				return `package config
+
+import (
+    "log"
+    "os"
+    "xorm.io/xorm"
+    _ "modernc.org/sqlite"
+)
+
+var DB *xorm.Engine
+
+func Connect() {
+    dbName := os.Getenv("DB_NAME")
+
+    var err error
+    DB, err = xorm.NewEngine("sqlite", dbName)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    if err := DB.Ping(); err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    log.Println("✅ Connected to the SQLite database successfully!")
+}`
+			}
 			return `package config
 
 import (
@@ -435,6 +710,76 @@ func Connect() {
     }
 
     log.Println("✅ Connected to the SQLite database successfully!")
+}`
+		case "sqlserver":
+			return `package config
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "xorm.io/xorm"
+    _ "github.com/microsoft/go-mssqldb"
+)
+
+var DB *xorm.Engine
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    var err error
+    DB, err = xorm.NewEngine("mssql", dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    if err := DB.Ping(); err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    log.Println("✅ Connected to the SQL Server database successfully!")
+}`
+		case "cockroachdb":
+			return `package config
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "xorm.io/xorm"
+    _ "github.com/lib/pq"
+)
+
+var DB *xorm.Engine
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    var err error
+    DB, err = xorm.NewEngine("postgres", dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %v", err)
+    }
+
+    if err := DB.Ping(); err != nil {
+        log.Fatalf("❌ Database ping failed: %v", err)
+    }
+
+    log.Println("✅ Connected to the CockroachDB database successfully!")
 }`
 		}
 	} else if ormLower == "ent" {
@@ -536,11 +881,246 @@ func Connect() {
 
     log.Println("✅ Connected to the SQLite database successfully!")
 }`
+		case "cockroachdb":
+			return fmt.Sprintf(`package config
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "os"
+
+    "entgo.io/ent/dialect"
+    "entgo.io/ent/dialect/sql"
+    _ "github.com/lib/pq"
+    "%s/ent"
+)
+
+var DB *ent.Client
+
+func Connect() {
+    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("postgresql://%%s:%%s@%%s:%%s/%%s?sslmode=disable",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+
+    // CockroachDB speaks the Postgres protocol and SQL dialect.
+    drv, err := sql.Open(dialect.Postgres, dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %%v", err)
+    }
+    DB = ent.NewClient(ent.Driver(drv))
+
+    if err := DB.Schema.Create(context.Background()); err != nil {
+        log.Fatalf("❌ Failed to create schema: %%v", err)
+    }
+
+    log.Println("✅ Connected to the CockroachDB database successfully!")
+}`, projectName)
+		}
+	} else if ormLower == "bun" {
+		return bunConfig(dbLower, sqliteDriver)
+	} else if ormLower == "sqlx/pgx" {
+		return sqlxConfig(dbLower, sqliteDriver)
+	}
+	return "None"
+}
+
+// sqlDriver returns the database/sql driver import and name for a database.
+func sqlDriver(database, sqliteDriver string) (string, string) {
+	switch database {
+	case "postgres", "cockroachdb":
+		return "github.com/jackc/pgx/v5/stdlib", "pgx"
+	case "mysql":
+		return "github.com/go-sql-driver/mysql", "mysql"
+	case "sqlserver":
+		return "github.com/microsoft/go-mssqldb", "sqlserver"
+	case "sqlite":
+		if sqliteDriver == "modernc" {
+			return "modernc.org/sqlite", "sqlite"
 		}
+		return "github.com/mattn/go-sqlite3", "sqlite3"
+	}
+	return "", ""
+}
+
+// dsnBlock returns the Connect() statements that read the .env settings into dsn.
+func dsnBlock(database string) string {
+	if database == "sqlite" {
+		return `    dsn := os.Getenv("DB_NAME")`
+	}
+
+	format := "postgres://%s:%s@%s:%s/%s?sslmode=disable"
+	args := "dbUser, dbPassword, dbHost, dbPort, dbName"
+	switch database {
+	case "mysql":
+		format = "%s:%s@tcp(%s:%s)/%s?parseTime=true"
+	case "sqlserver":
+		format = "sqlserver://%s:%s@%s:%s?database=%s"
+	}
+
+	return fmt.Sprintf(`    dbHost := os.Getenv("DB_HOST")
+    dbPort := os.Getenv("DB_PORT")
+    dbUser := os.Getenv("DB_USER")
+    dbPassword := os.Getenv("DB_PASSWORD")
+    dbName := os.Getenv("DB_NAME")
+
+    dsn := fmt.Sprintf("%s",
+        %s)`, format, args)
+}
+
+func fmtImport(database string) string {
+	if database == "sqlite" {
+		return ""
+	}
+	return "    \"fmt\"\n"
+}
+
+func bunConfig(database, sqliteDriver string) string {
+	dialects := map[string]string{
+		"postgres":    "pgdialect",
+		"cockroachdb": "pgdialect",
+		"mysql":       "mysqldialect",
+		"sqlite":      "sqlitedialect",
+		"sqlserver":   "mssqldialect",
+	}
+	dialect, ok := dialects[database]
+	if !ok {
+		return "None"
+	}
+	driverImport, driverName := sqlDriver(database, sqliteDriver)
+
+	return fmt.Sprintf(`package config
+
+import (
+    "database/sql"
+%s    "log"
+    "os"
+
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/dialect/%s"
+    _ "%s"
+)
+
+var DB *bun.DB
+
+func Connect() {
+%s
+
+    sqldb, err := sql.Open("%s", dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %%v", err)
+    }
+
+    DB = bun.NewDB(sqldb, %s.New())
+    if err := DB.Ping(); err != nil {
+        log.Fatalf("❌ Database ping failed: %%v", err)
+    }
+
+    log.Println("✅ Connected to the database successfully!")
+}`, fmtImport(database), dialect, driverImport, dsnBlock(database), driverName, dialect)
+}
+
+// sqlxConfig connects with pgxpool for Postgres-compatible databases and with
+// sqlx everywhere else.
+func sqlxConfig(database, sqliteDriver string) string {
+	switch database {
+	case "postgres", "cockroachdb":
+		return fmt.Sprintf(`package config
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "os"
+
+    "github.com/jackc/pgx/v5/pgxpool"
+)
+
+var DB *pgxpool.Pool
+
+func Connect() {
+%s
+
+    var err error
+    DB, err = pgxpool.New(context.Background(), dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %%v", err)
+    }
+
+    if err := DB.Ping(context.Background()); err != nil {
+        log.Fatalf("❌ Database ping failed: %%v", err)
+    }
+
+    log.Println("✅ Connected to the database successfully!")
+}`, dsnBlock(database))
+	case "mysql", "sqlite", "sqlserver":
+		driverImport, driverName := sqlDriver(database, sqliteDriver)
+		return fmt.Sprintf(`package config
+
+import (
+%s    "log"
+    "os"
+
+    "github.com/jmoiron/sqlx"
+    _ "%s"
+)
+
+var DB *sqlx.DB
+
+func Connect() {
+%s
+
+    var err error
+    DB, err = sqlx.Connect("%s", dsn)
+    if err != nil {
+        log.Fatalf("❌ Failed to connect to the database: %%v", err)
+    }
+
+    log.Println("✅ Connected to the database successfully!")
+}`, fmtImport(database), driverImport, dsnBlock(database), driverName)
 	}
 	return "None"
 }
 
+func getEnvFile(database string) string {
+	switch strings.ToLower(database) {
+	case "mysql":
+		return `DB_USER=root
+DB_PASSWORD=password
+DB_HOST=localhost
+DB_PORT=3306
+DB_NAME=mydb`
+	case "sqlite":
+		return `DB_NAME=mydb.db`
+	case "sqlserver":
+		return `DB_USER=sa
+DB_PASSWORD=YourStrong!Passw0rd
+DB_HOST=localhost
+DB_PORT=1433
+DB_NAME=master`
+	case "cockroachdb":
+		return `DB_USER=root
+DB_PASSWORD=
+DB_HOST=localhost
+DB_PORT=26257
+DB_NAME=defaultdb`
+	case "mongodb":
+		return `MONGO_URI=mongodb://localhost:27017
+MONGO_DB=mydb`
+	default:
+		return `DB_USER=postgres
+DB_PASSWORD=postgres
+DB_HOST=localhost
+DB_PORT=5432
+DB_NAME=mydb`
+	}
+}
+
 func getUtilsFile() string {
 	return `package utils
 
@@ -559,11 +1139,22 @@ func InitialEnv() error {
 }`
 }
 
-func getDockerFile(projectName string) string {
+func getDockerFile(projectName string, cgo bool) string {
+	cgoSetup := `
+ENV CGO_ENABLED=0
+`
+	if cgo {
+		cgoSetup = `
+# The mattn SQLite driver needs cgo and a C toolchain
+RUN apk add --no-cache gcc musl-dev
+ENV CGO_ENABLED=1
+`
+	}
+
 	return fmt.Sprintf(`FROM golang:1.17-alpine AS builder
 
 WORKDIR /app
-
+%s
 # Copy the Go Modules manifests
 COPY go.mod go.sum ./
 
@@ -592,7 +1183,7 @@ CMD ["./%s"]
 
 # Note: Customize the Dockerfile as needed for your specific project requirements.
 # For example, you may need to add environment variables, additional dependencies, or other configurations.
-`, projectName, projectName, projectName)
+`, cgoSetup, projectName, projectName, projectName)
 }
 
 func getDockerComposeFile(database string) string {
@@ -633,11 +1224,72 @@ func getDockerComposeFile(database string) string {
     volumes:
       - db-data:/data
 `
+	case "SQLServer":
+		dbService = `
+  db:
+    image: mcr.microsoft.com/mssql/server:2022-latest
+    environment:
+      ACCEPT_EULA: "Y"
+      MSSQL_SA_PASSWORD: "YourStrong!Passw0rd"
+    ports:
+      - "1433:1433"
+    volumes:
+      - db-data:/var/opt/mssql
+`
+	case "CockroachDB":
+		dbService = `
+  db:
+    image: cockroachdb/cockroach:latest
+    command: start-single-node --insecure
+    ports:
+      - "26257:26257"
+      - "8081:8080"
+    volumes:
+      - db-data:/cockroach/cockroach-data
+`
+	case "MongoDB":
+		dbService = `
+  db:
+    image: mongo:latest
+    environment:
+      MONGO_INITDB_DATABASE: mydb
+    ports:
+      - "27017:27017"
+    volumes:
+      - db-data:/data/db
+`
 
 	default:
 		dbService = ""
 	}
 
+	appEnv := `
+      - DB_HOST=db
+      - DB_PORT=5432
+      - DB_USER=user
+      - DB_PASSWORD=password
+      - DB_NAME=mydb`
+	switch database {
+	case "SQLServer":
+		appEnv = `
+      - DB_HOST=db
+      - DB_PORT=1433
+      - DB_USER=sa
+      - DB_PASSWORD=YourStrong!Passw0rd
+      - DB_NAME=master`
+	case "CockroachDB":
+		appEnv = `
+      - DB_HOST=db
+      - DB_PORT=26257
+      - DB_USER=root
+      - DB_PASSWORD=
+      - DB_NAME=defaultdb`
+	case "MongoDB":
+		appEnv = `
+      - MONGO_URI=mongodb://db:27017
+      - MONGO_DB=mydb`
+	}
+
 	return fmt.Sprintf(`version: '3.8'
 
 services:
@@ -647,15 +1299,10 @@ services:
       - "8080:8080"
     depends_on:
       - db
-    environment:
-      - DB_HOST=db
-      - DB_PORT=5432
-      - DB_USER=user
-      - DB_PASSWORD=password
-      - DB_NAME=mydb
+    environment:%s
 %s
 
 volumes:
   db-data:
-`, dbService)
+`, appEnv, dbService)
 }
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/spf13/cobra"
+)
+
+var DestroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Remove resources added by goscaf generate",
+}
+
+var destroyResourceCmd = &cobra.Command{
+	Use:   "resource <Name>",
+	Short: "Delete a resource's files and remove its route registration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		force, _ := cmd.Flags().GetBool("force")
+		m := loadManifest(projectPath)
+
+		if err := destroyResource(projectPath, m, resourceName(args[0]), force); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	destroyResourceCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	destroyResourceCmd.Flags().Bool("force", false, "Delete files even if they were modified since goscaf generated them")
+	DestroyCmd.AddCommand(destroyResourceCmd)
+}
+
+// destroyResource deletes the files goscaf wrote for a resource, removes its
+// call from SetupRoutes and drops it from the manifest. Files edited since
+// they were generated are only deleted with force or after confirmation.
+// Its migrations stay, since databases may have applied them; a new
+// migration drops the table instead.
+func destroyResource(projectPath string, m *manifest.Manifest, name string, force bool) error {
+	i := m.FindResource(name)
+	if i < 0 {
+		return fmt.Errorf("resource %s does not exist", name)
+	}
+	res := m.Resources[i]
+
+	var children []string
+	for _, other := range m.Resources {
+		if slices.Contains(other.BelongsTo, res.Name) {
+			children = append(children, other.Name)
+		}
+	}
+	switch len(children) {
+	case 0:
+	case 1:
+		return fmt.Errorf("%s belongs to %s; destroy it first", children[0], res.Name)
+	default:
+		return fmt.Errorf("%s belong to %s; destroy them first", strings.Join(children, ", "), res.Name)
+	}
+
+	var modified, migrations []string
+	for _, rel := range res.Files {
+		if strings.HasPrefix(rel, "migrations/") {
+			migrations = append(migrations, rel)
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(projectPath, rel)); err == nil && m.Modified(projectPath, rel) {
+			modified = append(modified, rel)
+		}
+	}
+	if len(modified) > 0 && !force {
+		fmt.Println("⚠️  These files were modified since goscaf generated them:")
+		for _, rel := range modified {
+			fmt.Println("   " + rel)
+		}
+		confirm := false
+		err := survey.AskOne(&survey.Confirm{Message: "Delete them anyway?"}, &confirm)
+		if err != nil || !confirm {
+			return fmt.Errorf("%s was not destroyed; pass --force to delete modified files", res.Name)
+		}
+	}
+
+	if len(migrations) > 0 {
+		if err := migrateDropTable(projectPath, m, res, migrations); err != nil {
+			return err
+		}
+	}
+
+	for _, rel := range res.Files {
+		if strings.HasPrefix(rel, "migrations/") {
+			continue
+		}
+		err := os.Remove(filepath.Join(projectPath, rel))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			fmt.Printf("🗑️  Deleted file: %s\n", rel)
+		}
+		delete(m.Files, rel)
+	}
+
+	unregisterInSetupRoutes(projectPath, m, templates.Resource{Name: res.Name}.RegisterFunc())
+
+	m.Resources = slices.Delete(m.Resources, i, i+1)
+	for j := range m.Resources {
+		other := &m.Resources[j]
+		other.HasMany = slices.DeleteFunc(other.HasMany, func(s string) bool { return s == res.Name })
+		if slices.Contains(res.BelongsTo, other.Name) {
+			if err := rerenderResource(projectPath, m, other.Name); err != nil {
+				return err
+			}
+		}
+	}
+	writeDocs(projectPath, m)
+	if err := m.Save(projectPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Destroyed resource %s (%s)\n", res.Name, res.Table)
+	if strings.ToLower(m.ORM) == "ent" {
+		fmt.Println("ℹ️  Run `go generate ./ent` to regenerate the Ent client without the removed schema")
+	}
+	return nil
+}
+
+// migrateDropTable adds a migration that drops a destroyed resource's table
+// and, going down, creates it again. The down step is rendered from the
+// resource's fields, or is the resource's own up migrations if goscaf did
+// not record its fields.
+func migrateDropTable(projectPath string, m *manifest.Manifest, res manifest.Resource, migrations []string) error {
+	var up, down string
+	if r, err := loadResource(m, res); err == nil {
+		down, up = templates.ResourceMigrationTemplate(r)
+	} else {
+		up = fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", res.Table)
+		for _, rel := range migrations {
+			if !strings.HasSuffix(rel, ".up.sql") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(projectPath, rel))
+			if err != nil {
+				return err
+			}
+			down += string(data)
+		}
+	}
+	_, err := appendMigration(projectPath, m, "drop_"+res.Table, up, down)
+	return err
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/astedit"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/openapi"
+	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/samznd/goscaf/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var generateDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Regenerate docs/openapi.json from the project's routes and models",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		m := loadManifest(projectPath)
+
+		if !writeDocs(projectPath, m) {
+			os.Exit(1)
+		}
+		if err := m.Save(projectPath); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ Updated docs/openapi.json")
+	},
+}
+
+func init() {
+	generateDocsCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	GenerateCmd.AddCommand(generateDocsCmd)
+}
+
+// writeDocs rebuilds docs/openapi.json, which the docs package embeds and
+// serves at /docs, and tracks it in the manifest. The docs package itself is
+// only written if it is missing. The caller saves the manifest.
+func writeDocs(projectPath string, m *manifest.Manifest) bool {
+	spec, err := openapi.Build(projectPath, m)
+	if err != nil {
+		fmt.Printf("⚠️  Could not update docs/openapi.json: %v\n", err)
+		return false
+	}
+
+	dir := filepath.Join(projectPath, "docs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Printf("⚠️  Could not update docs/openapi.json: %v\n", err)
+		return false
+	}
+	files := []string{filepath.Join(dir, "openapi.json")}
+	if err := utils.CreateFile(files[0], string(spec)); err != nil {
+		fmt.Printf("⚠️  Could not update docs/openapi.json: %v\n", err)
+		return false
+	}
+
+	pkg := filepath.Join(dir, "docs.go")
+	if _, err := os.Stat(pkg); os.IsNotExist(err) {
+		content := templates.DocsTemplate()
+		if formatted, err := format.Source([]byte(content)); err == nil {
+			content = string(formatted)
+		}
+		if err := utils.CreateFile(pkg, content); err != nil {
+			fmt.Printf("⚠️  Could not write docs/docs.go: %v\n", err)
+			return false
+		}
+		files = append(files, pkg)
+
+		mountDocs(projectPath, m)
+	}
+
+	if err := m.Track(projectPath, files...); err != nil {
+		fmt.Printf("⚠️  Could not hash docs files: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// mountDocs serves the docs package from SetupRoutes in projects created
+// before goscaf wrote it.
+func mountDocs(projectPath string, m *manifest.Manifest) {
+	stmts, imports := templates.DocsRoutes(m.Module, strings.ToLower(m.Framework))
+	err := editTracked(projectPath, m, routesFile, func(f *astedit.File) (bool, error) {
+		if f.Calls("SetupRoutes", "docs.Handler") || len(stmts) == 0 {
+			return false, nil
+		}
+		for _, stmt := range stmts {
+			if _, err := f.AppendStmt("SetupRoutes", stmt); err != nil {
+				return false, err
+			}
+		}
+		_, err := f.AddImports(imports...)
+		return true, err
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Could not update %s (%v); mount docs.Handler() at /docs in SetupRoutes yourself\n", routesFile, err)
+	}
+}
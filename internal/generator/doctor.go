@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/version"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+)
+
+// minGoVersion is the oldest Go toolchain the dependencies init installs
+// accept; current Fiber and Gin releases declare go 1.25.
+const minGoVersion = "go1.25"
+
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor [project]",
+	Short: "Diagnose the environment, and the project or the directory init would create",
+	Long: "Checks the Go toolchain, Go environment, CGO, Docker and file permissions that\n" +
+		"init and generate depend on. Inside a project it also checks the project against\n" +
+		manifest.FileName + "; with a project name it checks the directory init would create.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		sqliteDriver, _ := cmd.Flags().GetString("sqlite-driver")
+		target := ""
+		if len(args) == 1 {
+			target = args[0]
+		}
+
+		problems := 0
+		for _, d := range diagnose(projectPath, target, sqliteDriver) {
+			d.print()
+			if d.level == levelProblem {
+				problems++
+			}
+		}
+		if problems > 0 {
+			fmt.Printf("❌ %d problem(s) found\n", problems)
+			os.Exit(1)
+		}
+		fmt.Println("✅ No problems found")
+	},
+}
+
+func init() {
+	DoctorCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	DoctorCmd.Flags().String("sqlite-driver", "", "SQLite driver init will use: modernc or mattn (default: the project's)")
+}
+
+type level int
+
+const (
+	levelOK level = iota
+	levelWarning
+	levelProblem
+)
+
+// diagnosis is the result of one check. Warnings and problems come with
+// the fix to apply.
+type diagnosis struct {
+	level  level
+	detail string
+	fix    string
+}
+
+func ok(format string, args ...any) diagnosis {
+	return diagnosis{level: levelOK, detail: fmt.Sprintf(format, args...)}
+}
+
+func (d diagnosis) print() {
+	switch d.level {
+	case levelOK:
+		fmt.Println("✅ " + d.detail)
+	case levelWarning:
+		fmt.Println("⚠️  " + d.detail)
+	default:
+		fmt.Println("❌ " + d.detail)
+	}
+	if d.fix != "" {
+		fmt.Println("   → " + d.fix)
+	}
+}
+
+// diagnose runs every check. target is the directory init would create, if
+// any; otherwise the project at projectPath is checked when it has a
+// manifest.
+func diagnose(projectPath, target, sqliteDriver string) []diagnosis {
+	var m *manifest.Manifest
+	if target == "" {
+		m, _ = manifest.Load(projectPath)
+	}
+	if sqliteDriver == "" && m != nil && strings.EqualFold(m.Database, "sqlite") {
+		sqliteDriver = m.SQLiteDriver
+	}
+
+	env, err := goEnv("GOVERSION", "GOPATH", "GOMODCACHE", "GOPROXY", "GOFLAGS", "GOTOOLCHAIN", "CGO_ENABLED", "CC")
+	if err != nil {
+		return []diagnosis{{levelProblem, "Go toolchain not found: " + err.Error(), "Install Go " + strings.TrimPrefix(minGoVersion, "go") + " or newer from https://go.dev/dl and put it on PATH"}}
+	}
+
+	ds := []diagnosis{checkGoVersion(env, minGoVersion, "the dependencies init installs")}
+	if m != nil {
+		if data, err := os.ReadFile(filepath.Join(projectPath, "go.mod")); err == nil {
+			if mod, err := modfile.ParseLax("go.mod", data, nil); err == nil && mod.Go != nil {
+				ds = append(ds, checkGoVersion(env, "go"+mod.Go.Version, "the go directive in go.mod"))
+			}
+		}
+	}
+	ds = append(ds, checkGoEnv(env)...)
+	if sqliteDriver == "mattn" {
+		ds = append(ds, checkCgo(env))
+	}
+	if m != nil && m.RPC != "" && !strings.EqualFold(m.RPC, "none") {
+		if _, err := exec.LookPath("buf"); err != nil {
+			ds = append(ds, diagnosis{levelWarning, "buf not found; the RPC stubs in gen/ cannot be generated", "Install buf from https://buf.build/docs/installation, then run `buf generate`"})
+		} else {
+			ds = append(ds, ok("buf found"))
+		}
+	}
+	ds = append(ds, checkDocker())
+
+	dir := projectPath
+	if target != "" {
+		ds = append(ds, checkTarget(target))
+		dir = "."
+	}
+	ds = append(ds, checkWritable(dir))
+	return ds
+}
+
+// goEnv returns the given variables as reported by go env.
+func goEnv(names ...string) (map[string]string, error) {
+	out, err := exec.Command("go", append([]string{"env", "-json"}, names...)...).Output()
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]string{}
+	return env, json.Unmarshal(out, &env)
+}
+
+// checkGoVersion compares the installed toolchain with the version need
+// requires. With GOTOOLCHAIN=auto an older go downloads a newer toolchain
+// on demand, which needs network access.
+func checkGoVersion(env map[string]string, need, what string) diagnosis {
+	have := env["GOVERSION"]
+	if version.Compare(have, need) >= 0 {
+		return ok("%s satisfies %s (%s)", have, what, need)
+	}
+	fix := "Install Go " + strings.TrimPrefix(need, "go") + " or newer from https://go.dev/dl"
+	if strings.HasPrefix(env["GOTOOLCHAIN"], "local") {
+		return diagnosis{levelProblem, fmt.Sprintf("%s is older than %s, which %s requires, and GOTOOLCHAIN=local prevents downloading it", have, need, what),
+			fix + ", or run `go env -u GOTOOLCHAIN`"}
+	}
+	return diagnosis{levelWarning, fmt.Sprintf("%s is older than %s, which %s requires; go will download %s on first use", have, need, what, need), fix}
+}
+
+// checkGoEnv looks for settings that make go get or go mod tidy fail.
+func checkGoEnv(env map[string]string) []diagnosis {
+	var ds []diagnosis
+	switch proxy := env["GOPROXY"]; {
+	case proxy == "off":
+		ds = append(ds, diagnosis{levelProblem, "GOPROXY=off; init cannot download dependencies", "Run `go env -w GOPROXY=https://proxy.golang.org,direct`"})
+	case proxy == "":
+		ds = append(ds, diagnosis{levelWarning, "GOPROXY is empty; modules are fetched directly from their repositories", "Run `go env -w GOPROXY=https://proxy.golang.org,direct` unless you rely on direct fetches"})
+	default:
+		ds = append(ds, ok("GOPROXY=%s", proxy))
+	}
+
+	if flags := env["GOFLAGS"]; strings.Contains(flags, "-mod=vendor") || strings.Contains(flags, "-mod=readonly") {
+		ds = append(ds, diagnosis{levelProblem, "GOFLAGS=" + flags + " stops go get from updating go.mod", "Run `go env -u GOFLAGS`, or remove -mod from GOFLAGS"})
+	} else if flags != "" {
+		ds = append(ds, ok("GOFLAGS=%s", flags))
+	}
+
+	cache := env["GOMODCACHE"]
+	if cache == "" {
+		cache = filepath.Join(env["GOPATH"], "pkg", "mod")
+	}
+	// A fresh GOPATH has no cache yet; go creates it in the nearest
+	// directory that exists.
+	dir := existingParent(cache)
+	if d := checkWritable(dir); d.level != levelOK {
+		d.detail = "module cache " + d.detail
+		d.fix = "Make " + dir + " writable, or point GOMODCACHE at a writable directory with `go env -w GOMODCACHE=...`"
+		ds = append(ds, d)
+	} else if dir != cache {
+		ds = append(ds, ok("module cache %s does not exist yet and can be created", cache))
+	} else {
+		ds = append(ds, ok("module cache %s is writable", cache))
+	}
+	return ds
+}
+
+// existingParent returns dir, or its nearest ancestor if dir does not
+// exist.
+func existingParent(dir string) string {
+	for {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// checkCgo checks what the mattn SQLite driver needs: CGO and a C compiler.
+func checkCgo(env map[string]string) diagnosis {
+	if env["CGO_ENABLED"] != "1" {
+		return diagnosis{levelProblem, "the mattn SQLite driver needs CGO, but CGO_ENABLED=" + env["CGO_ENABLED"],
+			"Run `go env -w CGO_ENABLED=1` and install a C compiler, or use --sqlite-driver modernc"}
+	}
+	cc := strings.Fields(env["CC"])
+	if len(cc) == 0 {
+		cc = []string{"gcc"}
+	}
+	if _, err := exec.LookPath(cc[0]); err != nil {
+		return diagnosis{levelProblem, "the mattn SQLite driver needs a C compiler, but " + cc[0] + " was not found",
+			"Install gcc or clang (e.g. `apt install build-essential` or `xcode-select --install`), or use --sqlite-driver modernc"}
+	}
+	return ok("CGO is enabled and %s is available for the mattn SQLite driver", cc[0])
+}
+
+// checkDocker checks for the tools the generated docker-compose.yml needs.
+func checkDocker() diagnosis {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return diagnosis{levelWarning, "docker not found; the generated Dockerfile and docker-compose.yml cannot be used",
+			"Install Docker from https://docs.docker.com/get-docker/"}
+	}
+	if exec.Command("docker", "compose", "version").Run() == nil {
+		return ok("docker and docker compose found")
+	}
+	if _, err := exec.LookPath("docker-compose"); err == nil {
+		return ok("docker and docker-compose found")
+	}
+	return diagnosis{levelWarning, "docker compose not found; `docker compose up` will not start the database",
+		"Install the Compose plugin: https://docs.docker.com/compose/install/"}
+}
+
+// checkTarget checks that init can create the project directory.
+func checkTarget(target string) diagnosis {
+	entries, err := os.ReadDir(target)
+	switch {
+	case os.IsNotExist(err):
+		return ok("%s does not exist yet", target)
+	case err != nil:
+		return diagnosis{levelProblem, fmt.Sprintf("%s cannot be read: %v", target, err), "Choose another project name or fix the directory's permissions"}
+	case len(entries) > 0:
+		return diagnosis{levelProblem, target + " already exists and is not empty; init would overwrite its files",
+			"Choose another project name, or move " + target + " out of the way"}
+	}
+	return ok("%s exists but is empty", target)
+}
+
+// checkWritable checks that files can be created in dir.
+func checkWritable(dir string) diagnosis {
+	f, err := os.CreateTemp(dir, ".goscaf-doctor-")
+	if err != nil {
+		return diagnosis{levelProblem, fmt.Sprintf("%s is not writable: %v", dir, err), "Fix the permissions of " + dir + ", or run goscaf from a directory you own"}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	abs, _ := filepath.Abs(dir)
+	return ok("%s is writable", abs)
+}
@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/astedit"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/openapi"
+	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/samznd/goscaf/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var generateFromOpenAPICmd = &cobra.Command{
+	Use:   "from-openapi <spec>",
+	Short: "Generate DTOs, handlers, routes and service methods from an OpenAPI 3 spec",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		m := loadManifest(projectPath)
+
+		doc, err := openapi.Load(args[0])
+		if err != nil {
+			fmt.Printf("❌ Error: Could not read %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+		types, ops, err := doc.Resolve()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := generateAPI(projectPath, m, args[0], types, ops); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	generateFromOpenAPICmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	GenerateCmd.AddCommand(generateFromOpenAPICmd)
+}
+
+// generateAPI writes the code for an OpenAPI spec. DTOs, the service
+// interface and the routes are regenerated on every run; the handler and
+// service implementation belong to the user, so only methods for operations
+// they do not have yet are appended to them.
+func generateAPI(projectPath string, m *manifest.Manifest, spec string, types []templates.APIType, ops []templates.APIOperation) error {
+	framework := strings.ToLower(m.Framework)
+	source := filepath.Base(spec)
+
+	var written []string
+	write := func(name, content string) error {
+		path := filepath.Join(projectPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		content, err := preserveRegions(path, content)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if formatted, err := format.Source([]byte(content)); err == nil {
+			content = string(formatted)
+		}
+		if err := utils.CreateFile(path, content); err != nil {
+			return err
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	for _, f := range apiFiles(m, source, types, ops) {
+		if err := write(f.name, f.content); err != nil {
+			return err
+		}
+	}
+
+	owned := []struct {
+		name, recv, content string
+		methods             []goMethod
+	}{
+		{name: "internal/services/api_service.go", recv: "apiService", content: templates.APIServiceImplTemplate()},
+		{name: "internal/handlers/api_handler.go", recv: "APIHandler", content: templates.APIHandlerTemplate(m.Module)},
+	}
+	for _, op := range ops {
+		code, imports := templates.APIServiceStub(m.Module, op)
+		owned[0].methods = append(owned[0].methods, goMethod{op.Name, code, imports})
+		code, imports = templates.APIHandlerMethod(m.Module, framework, op)
+		owned[1].methods = append(owned[1].methods, goMethod{op.Name, code, imports})
+	}
+	if framework == "chi" {
+		if _, err := os.Stat(filepath.Join(projectPath, "internal/handlers/json.go")); os.IsNotExist(err) {
+			if err := write("internal/handlers/json.go", templates.ResourceJSONHelperTemplate()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range owned {
+		path := filepath.Join(projectPath, f.name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := write(f.name, f.content); err != nil {
+				return err
+			}
+		}
+		added, err := appendMethods(path, f.recv, f.methods)
+		if err != nil {
+			return fmt.Errorf("updating %s: %w", f.name, err)
+		}
+		if len(added) > 0 {
+			fmt.Printf("✅ Added %s to %s\n", strings.Join(added, ", "), f.name)
+			written = append(written, path)
+		}
+	}
+	reportRemovedOperations(filepath.Join(projectPath, "internal/handlers/api_handler.go"), ops)
+
+	registerInSetupRoutes(projectPath, m, templates.APIRegisterCall(framework))
+
+	if rel, err := filepath.Rel(projectPath, spec); err == nil && !strings.HasPrefix(rel, "..") {
+		spec = filepath.ToSlash(rel)
+	}
+	m.OpenAPI = spec
+	writeDocs(projectPath, m)
+	if err := m.Track(projectPath, written...); err != nil {
+		return err
+	}
+	if err := m.Save(projectPath); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Generated %d operation(s) from %s\n", len(ops), source)
+	return nil
+}
+
+// apiFiles renders the files goscaf owns for an OpenAPI spec. They are
+// rewritten on every run.
+func apiFiles(m *manifest.Manifest, source string, types []templates.APIType, ops []templates.APIOperation) []resourceFile {
+	framework := strings.ToLower(m.Framework)
+	return []resourceFile{
+		{"internal/dto/api.go", templates.Generated(templates.APITypesTemplate(source, types))},
+		{"internal/services/api.go", templates.Generated(templates.APIServiceTemplate(source, m.Module, ops))},
+		{"internal/routes/api_routes.go", templates.Generated(templates.APIRoutesTemplate(source, m.Module, framework, ops))},
+	}
+}
+
+// reportRemovedOperations lists handler methods whose operation is no longer
+// in the spec. They are left in place since they may hold user code.
+func reportRemovedOperations(path string, ops []templates.APIOperation) {
+	f, err := astedit.Load(path)
+	if err != nil {
+		return
+	}
+	existing := f.Methods("APIHandler")
+	for _, op := range ops {
+		delete(existing, op.Name)
+	}
+	for name := range existing {
+		fmt.Printf("⚠️  %s is no longer in the spec; delete its handler and service methods before building\n", name)
+	}
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/schema"
+)
+
+func TestResourceFromTableKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		ddl     string
+		wantErr string
+	}{
+		{"single key", "CREATE TABLE books (id INTEGER PRIMARY KEY, title TEXT);", ""},
+		{"no key", "CREATE TABLE events (name TEXT, at TIMESTAMP);", "no primary key"},
+		{"composite key", "CREATE TABLE book_tags (book_id INTEGER, tag TEXT, PRIMARY KEY (book_id, tag));", "composite primary key (book_id, tag) is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := schema.ParseDDL(tt.ddl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := &manifest.Manifest{Module: "app", Framework: "Gin", Database: "SQLite", ORM: "GORM"}
+			r, err := resourceFromTable(m, tables[0])
+			if tt.wantErr == "" {
+				if err != nil || r.Table != tables[0].Name {
+					t.Errorf("resourceFromTable() = %q, %v", r.Table, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resourceFromTable() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/astedit"
+	"github.com/samznd/goscaf/internal/manifest"
+)
+
+// routesFile is the project file holding SetupRoutes.
+var routesFile = filepath.Join("internal", "routes", "routes.go")
+
+// registerInSetupRoutes adds call to SetupRoutes in internal/routes/routes.go
+// unless SetupRoutes already calls the same function. The file stays tracked
+// in the manifest only if the user had not edited it.
+func registerInSetupRoutes(projectPath string, m *manifest.Manifest, call string) {
+	fn, _, _ := strings.Cut(call, "(")
+	err := editTracked(projectPath, m, routesFile, func(f *astedit.File) (bool, error) {
+		if f.Calls("SetupRoutes", fn) {
+			return false, nil
+		}
+		return f.AppendStmt("SetupRoutes", call)
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Could not update %s (%v); call %s from SetupRoutes yourself\n", routesFile, err, call)
+	}
+}
+
+// unregisterInSetupRoutes removes the call to fn from SetupRoutes in
+// internal/routes/routes.go, the inverse of registerInSetupRoutes.
+func unregisterInSetupRoutes(projectPath string, m *manifest.Manifest, fn string) {
+	err := editTracked(projectPath, m, routesFile, func(f *astedit.File) (bool, error) {
+		n, err := f.RemoveCalls("SetupRoutes", fn)
+		return n > 0, err
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Could not update %s (%v); remove the call to %s from SetupRoutes yourself\n", routesFile, err, fn)
+	}
+}
+
+// editTracked applies edit to a project file that may hold user code and
+// saves it if the edit changed anything. The file stays tracked in the
+// manifest only if the user had not edited it.
+func editTracked(projectPath string, m *manifest.Manifest, rel string, edit func(*astedit.File) (bool, error)) error {
+	path := filepath.Join(projectPath, rel)
+	unmodified := !m.Modified(projectPath, filepath.ToSlash(rel))
+	f, err := astedit.Load(path)
+	if err != nil {
+		return err
+	}
+	changed, err := edit(f)
+	if err != nil || !changed {
+		return err
+	}
+	if err := f.Save(); err != nil {
+		return err
+	}
+	if unmodified {
+		return m.Track(projectPath, path)
+	}
+	return nil
+}
+
+// goMethod is a method to add to a Go file along with the imports it uses.
+type goMethod struct {
+	name    string
+	code    string
+	imports []string
+}
+
+// appendMethods adds each method whose name the receiver type does not have
+// yet to the end of the file, leaving existing methods untouched, and returns
+// the names of the methods it added.
+func appendMethods(path, recv string, methods []goMethod) ([]string, error) {
+	f, err := astedit.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	existing := f.Methods(recv)
+
+	var added []string
+	for _, method := range methods {
+		if existing[method.name] {
+			continue
+		}
+		if _, err := f.AppendDecl(method.code); err != nil {
+			return nil, fmt.Errorf("method %s: %w", method.name, err)
+		}
+		if _, err := f.AddImports(method.imports...); err != nil {
+			return nil, err
+		}
+		added = append(added, method.name)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return added, f.Save()
+}
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/pack"
+	"gopkg.in/yaml.v3"
+)
+
+// Hook stages, named as in the hooks section of pack.yaml and of a hooks
+// file.
+const (
+	hookPreRender   = "pre_render"
+	hookPost        = "post"
+	hookPostInstall = "post_install"
+)
+
+// loadHooks reads a hooks file: the hooks section of a pack.yaml on its
+// own, for projects init creates without a pack.
+func loadHooks(path string) (pack.Hooks, error) {
+	var file struct {
+		Hooks pack.Hooks `yaml:"hooks"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file.Hooks, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return file.Hooks, fmt.Errorf("%s: %w", path, err)
+	}
+	return file.Hooks, nil
+}
+
+// mergeHooks returns the hooks of a followed, stage by stage, by those of b.
+func mergeHooks(a, b pack.Hooks) pack.Hooks {
+	return pack.Hooks{
+		PreRender:   append(append([]string{}, a.PreRender...), b.PreRender...),
+		Post:        append(append([]string{}, a.Post...), b.Post...),
+		PostInstall: append(append([]string{}, a.PostInstall...), b.PostInstall...),
+	}
+}
+
+// runHooks runs the hooks of a stage in the project directory, stopping at
+// the first that fails.
+func runHooks(projectPath, stage string, hooks []string, m *manifest.Manifest) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	env := append(os.Environ(), hookEnv(projectPath, stage, m)...)
+	for _, hook := range hooks {
+		fmt.Printf("📦 Running %s hook: %s\n", stage, hook)
+		cmd := exec.Command("sh", "-c", hook)
+		cmd.Dir = projectPath
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", stage, hook, err)
+		}
+	}
+	return nil
+}
+
+// runPostHooks runs hooks that come after the files are written. Those
+// files stay if a hook fails, and the error says so.
+func runPostHooks(projectPath, stage string, hooks []string, m *manifest.Manifest) error {
+	if err := runHooks(projectPath, stage, hooks, m); err != nil {
+		return fmt.Errorf("%w; the project in %s is kept, since hooks run after it is written", err, projectPath)
+	}
+	return nil
+}
+
+// hookEnv returns the environment variables that tell a hook about the
+// project: the stage, the project's directory, name and module, the choices
+// recorded in the manifest and, for a pack, the pack and every answer as
+// GOSCAF_ANSWER_<NAME>.
+func hookEnv(projectPath, stage string, m *manifest.Manifest) []string {
+	dir, err := filepath.Abs(projectPath)
+	if err != nil {
+		dir = projectPath
+	}
+	env := []string{
+		"GOSCAF_HOOK=" + stage,
+		"GOSCAF_VERSION=" + Version,
+		"GOSCAF_PROJECT_DIR=" + dir,
+		"GOSCAF_PROJECT_NAME=" + filepath.Base(dir),
+		"GOSCAF_MODULE=" + m.Module,
+		"GOSCAF_FRAMEWORK=" + m.Framework,
+		"GOSCAF_DATABASE=" + m.Database,
+		"GOSCAF_ORM=" + m.ORM,
+		"GOSCAF_RPC=" + m.RPC,
+		"GOSCAF_SQLITE_DRIVER=" + m.SQLiteDriver,
+	}
+	if m.Pack != nil {
+		env = append(env, "GOSCAF_PACK_NAME="+m.Pack.Name, "GOSCAF_PACK_VERSION="+m.Pack.Version)
+		names := make([]string, 0, len(m.Pack.Answers))
+		for name := range m.Pack.Answers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			env = append(env, fmt.Sprintf("GOSCAF_ANSWER_%s=%v", envName(name), m.Pack.Answers[name]))
+		}
+	}
+	return env
+}
+
+// envName turns a prompt name into the upper-case letters, digits and
+// underscores of an environment variable name.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
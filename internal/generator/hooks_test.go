@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samznd/goscaf/internal/manifest"
+)
+
+func TestRunHooks(t *testing.T) {
+	dir := t.TempDir()
+	m := &manifest.Manifest{Module: "app"}
+
+	hooks := []string{`echo "$GOSCAF_HOOK" > stage`, "exit 3", "touch after"}
+	err := runPostHooks(dir, hookPost, hooks, m)
+	if err == nil || !strings.Contains(err.Error(), `post hook "exit 3" failed`) {
+		t.Fatalf("runPostHooks() error = %v, want the failing hook named", err)
+	}
+	if !strings.Contains(err.Error(), "is kept") {
+		t.Errorf("runPostHooks() error = %v, want it to say the project is kept", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "stage")); err != nil || string(data) != "post\n" {
+		t.Errorf("stage = %q, %v; want the hook environment", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "after")); !os.IsNotExist(err) {
+		t.Errorf("a hook after the failing one ran")
+	}
+
+	if err := runHooks(dir, hookPreRender, []string{"true"}, m); err != nil {
+		t.Errorf("runHooks() error = %v", err)
+	}
+}
@@ -4,28 +4,61 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/pack"
 	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/samznd/goscaf/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 // initCmd represents the init command
 var InitCmd = &cobra.Command{
-	Use:   "init",
+	Use:   "init [name]",
 	Short: "Initialize a new Go web application",
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		var projectName, backend, database, orm string
+		var projectName, backend, database, orm, rpc string
 
-		err := survey.AskOne(&survey.Input{Message: "What is your project name?"}, &projectName)
-		if err != nil {
+		sqliteDriver, _ := cmd.Flags().GetString("sqlite-driver")
+		if sqliteDriver != "modernc" && sqliteDriver != "mattn" {
+			fmt.Printf("❌ Error: Invalid SQLite driver: %s (expected modernc or mattn)\n", sqliteDriver)
+			os.Exit(1)
+		}
+
+		if len(args) == 1 {
+			projectName = args[0]
+		} else if err := survey.AskOne(&survey.Input{Message: "What is your project name?"}, &projectName); err != nil {
 			fmt.Println("\nOperation canceled by user.")
 			os.Exit(1)
 		}
-		err = survey.AskOne(&survey.Select{
+
+		var hooks pack.Hooks
+		if hooksPath, _ := cmd.Flags().GetString("hooks"); hooksPath != "" {
+			var err error
+			if hooks, err = loadHooks(hooksPath); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		packSource, _ := cmd.Flags().GetString("pack")
+		lockPath, _ := cmd.Flags().GetString("lock")
+		if packSource != "" || lockPath != "" {
+			sets, _ := cmd.Flags().GetStringArray("set")
+			if err := initFromPack(filepath.Join(".", projectName), packSource, lockPath, sets, hooks); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		err := survey.AskOne(&survey.Select{
 			Message: "Choose your web framework:",
-			Options: []string{"Fiber", "Gin", "Echo", "Chi", "Iris"},
+			Options: frameworkNames(),
 		}, &backend)
 		if err != nil {
 			fmt.Println("\nOperation canceled by user.")
@@ -33,7 +66,7 @@ var InitCmd = &cobra.Command{
 		}
 		err = survey.AskOne(&survey.Select{
 			Message: "Choose your database system:",
-			Options: []string{"Postgres", "MySQL", "SQLite"},
+			Options: databaseNames(),
 		}, &database)
 		if err != nil {
 			fmt.Println("\nOperation canceled by user.")
@@ -42,17 +75,22 @@ var InitCmd = &cobra.Command{
 
 		orm = "none"
 
+		// ORMs that cannot target the chosen database are not offered.
+		orms := ormNamesFor(database)
+
 		var useORM bool
-		err = survey.AskOne(&survey.Confirm{Message: "Would you like to use an ORM?"}, &useORM)
-		if err != nil {
-			fmt.Println("\nOperation canceled by user.")
-			os.Exit(1)
+		if len(orms) > 0 {
+			err = survey.AskOne(&survey.Confirm{Message: "Would you like to use an ORM?"}, &useORM)
+			if err != nil {
+				fmt.Println("\nOperation canceled by user.")
+				os.Exit(1)
+			}
 		}
 
 		if useORM {
 			err = survey.AskOne(&survey.Select{
 				Message: "Choose your ORM framework:",
-				Options: []string{"GORM", "XORM", "Ent", "SQLBoiler"},
+				Options: orms,
 			}, &orm)
 			if err != nil {
 				fmt.Println("\nOperation canceled by user.")
@@ -60,11 +98,76 @@ var InitCmd = &cobra.Command{
 			}
 		}
 
+		if status, note := compatibility(backend, database, orm); status == Experimental {
+			fmt.Printf("⚠️  %s with %s and %s is experimental: %s\n", backend, database, orm, note)
+		}
+
+		err = survey.AskOne(&survey.Select{
+			Message: "Would you like to expose an RPC API?",
+			Options: []string{"None", "Connect-RPC", "gRPC-Gateway"},
+		}, &rpc)
+		if err != nil {
+			fmt.Println("\nOperation canceled by user.")
+			os.Exit(1)
+		}
+
 		projectPath := filepath.Join(".", projectName)
 		os.MkdirAll(projectPath, os.ModePerm)
-
-		ScaffoldBackendCmd.Run(cmd, []string{projectPath, backend, database, orm})
-		templates.InitTemplateCmd.Run(cmd, []string{projectPath, backend, orm})
-		fmt.Println("✅ Project initialized successfully!")
+		if err := initProject(projectPath, backend, database, orm, rpc, sqliteDriver, hooks); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
 	},
 }
+
+// initProject writes a new project without a pack, running its hooks: the
+// pre_render hooks before the first file is written, the post hooks once
+// every file is written and the post_install hooks once the dependencies
+// are installed. It returns the error of the first hook that fails.
+func initProject(projectPath, backend, database, orm, rpc, sqliteDriver string, hooks pack.Hooks) error {
+	m := &manifest.Manifest{
+		Version:   Version,
+		Module:    projectPath,
+		Framework: backend,
+		Database:  database,
+		ORM:       orm,
+		RPC:       rpc,
+	}
+	sqliteDriver = effectiveSQLiteDriver(orm, sqliteDriver)
+	if strings.EqualFold(database, "sqlite") {
+		m.SQLiteDriver = sqliteDriver
+	}
+	if err := runHooks(projectPath, hookPreRender, hooks.PreRender, m); err != nil {
+		return err
+	}
+
+	scaffoldBackendFiles(projectPath, backend, database, orm, strings.ToLower(rpc), sqliteDriver)
+	templates.InitTemplateCmd.Run(nil, []string{projectPath, backend, orm, rpc, database})
+	writeDocs(projectPath, m)
+	if err := m.Track(projectPath, utils.WrittenFiles()...); err != nil {
+		fmt.Printf("Error hashing generated files: %v\n", err)
+	}
+	if err := m.Save(projectPath); err != nil {
+		fmt.Printf("Error creating %s: %v\n", manifest.FileName, err)
+	}
+	if err := runPostHooks(projectPath, hookPost, hooks.Post, m); err != nil {
+		return err
+	}
+
+	// Initialize go.mod and install dependencies
+	installDependencies(projectPath, backend, database, orm, strings.ToLower(rpc), sqliteDriver)
+	if err := runPostHooks(projectPath, hookPostInstall, hooks.PostInstall, m); err != nil {
+		return err
+	}
+	fmt.Println("✅ Project initialized successfully!")
+	return nil
+}
+
+func init() {
+	InitCmd.Flags().String("sqlite-driver", "modernc", "SQLite driver: modernc (pure Go) or mattn (requires cgo)")
+	InitCmd.Flags().String("pack", "", "Create the project from a template pack: a directory or git+<url>[@<ref>]")
+	InitCmd.Flags().String("lock", "", "Create the project from the pack pinned by a "+pack.LockFileName+" file")
+	InitCmd.Flags().StringArray("set", nil, "Answer a pack prompt: key=value; may be repeated")
+	InitCmd.Flags().String("hooks", "", "YAML file whose hooks section lists commands to run during init, after a pack's own hooks")
+	InitCmd.MarkFlagsMutuallyExclusive("pack", "lock")
+}
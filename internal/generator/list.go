@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the frameworks, databases, ORMs and features goscaf supports",
+}
+
+// listEntry is a framework, database, ORM or feature. Modules are pinned
+// to the versions init installs.
+type listEntry struct {
+	Name        string   `json:"name"`
+	Status      Status   `json:"status"`
+	Description string   `json:"description,omitempty"`
+	Databases   []string `json:"databases,omitempty"`
+	Modules     []string `json:"modules,omitempty"`
+	Note        string   `json:"note,omitempty"`
+}
+
+// matrixEntry is a framework, database and ORM combination with what init
+// would install for it and the features that apply to it.
+type matrixEntry struct {
+	Framework    string   `json:"framework"`
+	Database     string   `json:"database"`
+	ORM          string   `json:"orm"`
+	Status       Status   `json:"status"`
+	Note         string   `json:"note,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+	Features     []string `json:"features,omitempty"`
+}
+
+var listFrameworksCmd = &cobra.Command{
+	Use:   "frameworks",
+	Short: "List the web frameworks",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var entries []listEntry
+		for _, fw := range Frameworks {
+			modules, _ := frameworkModules(fw.Name)
+			entries = append(entries, listEntry{Name: fw.Name, Status: fw.Status, Modules: pinnedAll(modules)})
+		}
+		printList(cmd, entries)
+	},
+}
+
+var listDatabasesCmd = &cobra.Command{
+	Use:   "databases",
+	Short: "List the database systems",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var entries []listEntry
+		for _, db := range Databases {
+			modules, _ := driverModules(db.Name, "modernc")
+			if strings.EqualFold(db.Name, "sqlite") {
+				mattn, _ := driverModules(db.Name, "mattn")
+				modules = append(modules, mattn...)
+			}
+			entries = append(entries, listEntry{Name: db.Name, Status: db.Status, Modules: pinnedAll(modules), Note: db.Note})
+		}
+		printList(cmd, entries)
+	},
+}
+
+var listORMsCmd = &cobra.Command{
+	Use:   "orms",
+	Short: "List the ORMs and the databases each can target",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var entries []listEntry
+		for _, orm := range ORMs {
+			var modules []string
+			if orm.Status != Unsupported {
+				for _, db := range orm.Databases {
+					mods, _ := ormModules(orm.Name, db, effectiveSQLiteDriver(orm.Name, "modernc"))
+					modules = appendNew(modules, mods...)
+				}
+			}
+			entries = append(entries, listEntry{Name: orm.Name, Status: orm.Status, Databases: orm.Databases, Modules: pinnedAll(modules), Note: orm.Note})
+		}
+		printList(cmd, entries)
+	},
+}
+
+var listFeaturesCmd = &cobra.Command{
+	Use:   "features",
+	Short: "List the optional features",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var entries []listEntry
+		for _, f := range Features {
+			entries = append(entries, listEntry{Name: f.Name, Status: f.Status, Description: f.Description,
+				Modules: pinnedAll(rpcModules(f.Name)), Note: f.Note})
+		}
+		printList(cmd, entries)
+	},
+}
+
+var listMatrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "List every framework, database and ORM combination and whether it is supported",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		framework, _ := cmd.Flags().GetString("framework")
+		database, _ := cmd.Flags().GetString("database")
+		orm, _ := cmd.Flags().GetString("orm")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		entries := matrix(framework, database, orm)
+		if asJSON {
+			printJSON(entries)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println("❌ Error: No combination matches the given framework, database and ORM")
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FRAMEWORK\tDATABASE\tORM\tSTATUS\tNOTE")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Framework, e.Database, e.ORM, e.Status, e.Note)
+		}
+		w.Flush()
+		if len(entries) == 1 && entries[0].Status != Unsupported {
+			fmt.Println("\nDependencies:")
+			for _, module := range entries[0].Dependencies {
+				fmt.Println("  " + module)
+			}
+			fmt.Println("Features: " + strings.Join(entries[0].Features, ", "))
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{listFrameworksCmd, listDatabasesCmd, listORMsCmd, listFeaturesCmd, listMatrixCmd} {
+		c.Flags().Bool("json", false, "Print the list as JSON")
+		ListCmd.AddCommand(c)
+	}
+	listMatrixCmd.Flags().String("framework", "", "Only list combinations with this framework")
+	listMatrixCmd.Flags().String("database", "", "Only list combinations with this database")
+	listMatrixCmd.Flags().String("orm", "", "Only list combinations with this ORM, or none")
+}
+
+// matrix returns every combination of the registry's frameworks, databases
+// and ORMs, plus no ORM, that matches the non-empty filters.
+func matrix(framework, database, orm string) []matrixEntry {
+	orms := []string{"None"}
+	for _, o := range ORMs {
+		orms = append(orms, o.Name)
+	}
+	match := func(filter, name string) bool { return filter == "" || strings.EqualFold(filter, name) }
+
+	entries := []matrixEntry{}
+	for _, fw := range Frameworks {
+		for _, db := range Databases {
+			for _, o := range orms {
+				if !match(framework, fw.Name) || !match(database, db.Name) || !match(orm, o) {
+					continue
+				}
+				e := matrixEntry{Framework: fw.Name, Database: db.Name, ORM: o}
+				e.Status, e.Note = compatibility(fw.Name, db.Name, o)
+				if e.Status != Unsupported {
+					modules, _ := stackDependencies(fw.Name, db.Name, o, effectiveSQLiteDriver(o, "modernc"))
+					e.Dependencies = pinnedAll(modules)
+					for _, f := range Features {
+						if f.Status != Unsupported && (f.Supports == nil || f.Supports(db.Name, o)) {
+							e.Features = append(e.Features, f.Name)
+						}
+					}
+				}
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries
+}
+
+func printList(cmd *cobra.Command, entries []listEntry) {
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		printJSON(entries)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tDETAILS\tMODULES")
+	for _, e := range entries {
+		detail := e.Description
+		if len(e.Databases) > 0 {
+			detail = strings.Join(e.Databases, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Name, e.Status, detail, strings.Join(e.Modules, " "))
+	}
+	w.Flush()
+	for _, e := range entries {
+		if e.Note != "" {
+			fmt.Printf("ℹ️  %s: %s\n", e.Name, e.Note)
+		}
+	}
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func pinnedAll(modules []string) []string {
+	out := make([]string, 0, len(modules))
+	for _, module := range modules {
+		out = append(out, pinned(module))
+	}
+	return out
+}
+
+// appendNew appends the values not already in list.
+func appendNew(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,325 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage database migrations of a generated project",
+}
+
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a timestamped up/down migration pair",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+
+		m, err := manifest.Load(projectPath)
+		if err != nil {
+			fmt.Printf("❌ Error: Could not read %s: %v\n", manifest.FileName, err)
+			os.Exit(1)
+		}
+		if !usesMigrations(m.Database, m.ORM) {
+			fmt.Printf("❌ Error: %s projects using %s do not use SQL migrations\n", m.Database, m.ORM)
+			os.Exit(1)
+		}
+
+		database := strings.ToLower(m.Database)
+		up := fmt.Sprintf(`-- %s (%s)
+-- CREATE TABLE example (
+--     %s
+-- );
+`, args[0], m.Database, idColumn(database))
+		down := fmt.Sprintf(`-- %s (%s)
+-- DROP TABLE IF EXISTS example;
+`, args[0], m.Database)
+
+		if _, err := appendMigration(projectPath, m, args[0], up, down); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	migrateCreateCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	MigrateCmd.AddCommand(migrateCreateCmd)
+}
+
+// usesMigrations reports whether a project manages its schema with the
+// embedded SQL migrations. Ent creates its own schema and MongoDB has none.
+func usesMigrations(database, orm string) bool {
+	db, ok := findDatabase(database)
+	return ok && db.SQL && strings.ToLower(orm) != "ent"
+}
+
+// migratesWithGoose reports whether a project's migration runner uses goose
+// rather than golang-migrate, whose SQLite driver clashes with the one GORM
+// uses on modernc.
+func migratesWithGoose(database, orm, sqliteDriver string) bool {
+	return strings.EqualFold(database, "sqlite") && sqliteDriver == "modernc" && strings.EqualFold(orm, "gorm")
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// appendMigration writes a timestamped up/down pair to migrations/ and
+// records both files in the manifest. Resource generation uses it to add the
+// migration for each new table.
+func appendMigration(projectPath string, m *manifest.Manifest, name, up, down string) ([]string, error) {
+	slug := strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return nil, fmt.Errorf("invalid migration name %q", name)
+	}
+
+	dir := filepath.Join(projectPath, "migrations")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	version, err := nextVersion(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{
+		filepath.Join(dir, fmt.Sprintf("%d_%s.up.sql", version, slug)),
+		filepath.Join(dir, fmt.Sprintf("%d_%s.down.sql", version, slug)),
+	}
+	for i, content := range []string{up, down} {
+		if _, err := os.Stat(files[i]); err == nil {
+			return nil, fmt.Errorf("migration %s already exists", files[i])
+		}
+		if err := utils.CreateFile(files[i], content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.Track(projectPath, files...); err != nil {
+		return nil, err
+	}
+	return files, m.Save(projectPath)
+}
+
+// nextVersion returns the current UTC timestamp as a migration version, bumped
+// past the newest existing migration so versions stay unique and ordered.
+func nextVersion(dir string) (uint64, error) {
+	version, _ := strconv.ParseUint(time.Now().UTC().Format("20060102150405"), 10, 64)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		if existing, err := strconv.ParseUint(prefix, 10, 64); err == nil && existing >= version {
+			version = existing + 1
+		}
+	}
+	return version, nil
+}
+
+// idColumn returns the auto-incrementing primary key column for a dialect.
+func idColumn(database string) string {
+	switch database {
+	case "mysql":
+		return "id BIGINT AUTO_INCREMENT PRIMARY KEY"
+	case "sqlite":
+		return "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	case "sqlserver":
+		return "id BIGINT IDENTITY(1,1) PRIMARY KEY"
+	default:
+		return "id BIGSERIAL PRIMARY KEY"
+	}
+}
+
+func getInitMigration(database string) (string, string) {
+	var up string
+	switch database {
+	case "mysql":
+		up = `CREATE TABLE IF NOT EXISTS messages (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+	case "sqlite":
+		up = `CREATE TABLE IF NOT EXISTS messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    message TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+	case "sqlserver":
+		up = `IF OBJECT_ID('messages', 'U') IS NULL
+CREATE TABLE messages (
+    id BIGINT IDENTITY(1,1) PRIMARY KEY,
+    message NVARCHAR(MAX) NOT NULL,
+    created_at DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME()
+);
+`
+	default:
+		up = `CREATE TABLE IF NOT EXISTS messages (
+    id BIGSERIAL PRIMARY KEY,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+	}
+
+	return up, "DROP TABLE IF EXISTS messages;\n"
+}
+
+// getGormSQLiteMigrateFile returns config/migrate.go for GORM on modernc
+// SQLite. glebarez/sqlite registers the database/sql driver name "sqlite",
+// as modernc.org/sqlite, which golang-migrate's sqlite driver imports, does:
+// a binary with both panics at startup. So goose, which registers no
+// driver, applies each up/down pair over GORM's own connection.
+func getGormSQLiteMigrateFile(projectName string) string {
+	return fmt.Sprintf(`package config
+
+import (
+    "context"
+    "database/sql"
+    "io/fs"
+    "log"
+    "strconv"
+    "strings"
+
+    "github.com/pressly/goose/v3"
+    "%s/migrations"
+)
+
+// Migrate applies the embedded migrations that have not run yet, recording
+// them in the goose_db_version table.
+func Migrate() {
+    db, err := DB.DB()
+    if err != nil {
+        log.Fatalf("❌ Failed to initialize migrations: %%v", err)
+    }
+
+    files, err := fs.Glob(migrations.FS, "*.up.sql")
+    if err != nil {
+        log.Fatalf("❌ Failed to load migrations: %%v", err)
+    }
+    var steps []*goose.Migration
+    for _, up := range files {
+        prefix, _, _ := strings.Cut(up, "_")
+        version, err := strconv.ParseInt(prefix, 10, 64)
+        if err != nil {
+            log.Fatalf("❌ Invalid migration file name %%s", up)
+        }
+        down := strings.TrimSuffix(up, ".up.sql") + ".down.sql"
+        steps = append(steps, goose.NewGoMigration(version, runFile(up), runFile(down)))
+    }
+
+    provider, err := goose.NewProvider(goose.DialectSQLite3, db, nil, goose.WithGoMigrations(steps...))
+    if err != nil {
+        log.Fatalf("❌ Failed to initialize migrations: %%v", err)
+    }
+    if _, err := provider.Up(context.Background()); err != nil {
+        log.Fatalf("❌ Failed to apply migrations: %%v", err)
+    }
+
+    log.Println("✅ Database migrations applied successfully!")
+}
+
+// runFile runs a migration file inside the migration's transaction.
+func runFile(name string) *goose.GoFunc {
+    return &goose.GoFunc{RunTx: func(ctx context.Context, tx *sql.Tx) error {
+        query, err := fs.ReadFile(migrations.FS, name)
+        if err != nil {
+            return err
+        }
+        _, err = tx.ExecContext(ctx, string(query))
+        return err
+    }}
+}
+`, projectName)
+}
+
+func getMigrationsEmbedFile() string {
+	return `// Package migrations embeds the numbered up/down SQL files in this
+// directory so the binary can apply them at startup.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
+`
+}
+
+func getMigrateFile(projectName, database, orm, sqliteDriver string) string {
+	if migratesWithGoose(database, orm, sqliteDriver) {
+		return getGormSQLiteMigrateFile(projectName)
+	}
+	driver := "postgres"
+	url := `fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+        os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))`
+	switch database {
+	case "cockroachdb":
+		driver = "cockroachdb"
+		url = `fmt.Sprintf("cockroachdb://%s:%s@%s:%s/%s?sslmode=disable",
+        os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))`
+	case "mysql":
+		driver = "mysql"
+		url = `fmt.Sprintf("mysql://%s:%s@tcp(%s:%s)/%s?multiStatements=true",
+        os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))`
+	case "sqlserver":
+		driver = "sqlserver"
+		url = `fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s",
+        os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))`
+	case "sqlite":
+		driver = "sqlite"
+		url = `fmt.Sprintf("sqlite://%s", os.Getenv("DB_NAME"))`
+		if sqliteDriver == "mattn" {
+			driver = "sqlite3"
+			url = `fmt.Sprintf("sqlite3://%s", os.Getenv("DB_NAME"))`
+		}
+	}
+
+	return fmt.Sprintf(`package config
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "os"
+
+    "github.com/golang-migrate/migrate/v4"
+    _ "github.com/golang-migrate/migrate/v4/database/%s"
+    "github.com/golang-migrate/migrate/v4/source/iofs"
+    "%s/migrations"
+)
+
+// Migrate applies the embedded migrations that have not run yet.
+func Migrate() {
+    source, err := iofs.New(migrations.FS, ".")
+    if err != nil {
+        log.Fatalf("❌ Failed to load migrations: %%v", err)
+    }
+
+    m, err := migrate.NewWithSourceInstance("iofs", source, %s)
+    if err != nil {
+        log.Fatalf("❌ Failed to initialize migrations: %%v", err)
+    }
+
+    if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+        log.Fatalf("❌ Failed to apply migrations: %%v", err)
+    }
+
+    log.Println("✅ Database migrations applied successfully!")
+}
+`, driver, projectName, url)
+}
@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/pack"
+	"github.com/spf13/cobra"
+)
+
+var PackCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Create and check template packs",
+}
+
+// initFromPack creates a project from a template pack: from source, or from
+// the pack a lockfile pins when lockPath is set. sets holds key=value
+// answers; the other prompts are asked. extra hooks run after the pack's.
+func initFromPack(projectPath, source, lockPath string, sets []string, extra pack.Hooks) error {
+	var f *pack.Fetched
+	var err error
+	if lockPath != "" {
+		lock, err := pack.ReadLock(lockPath)
+		if err != nil {
+			return err
+		}
+		f, err = pack.FetchLocked(lock)
+		if err != nil {
+			return err
+		}
+	} else if f, err = pack.Fetch(source); err != nil {
+		return err
+	}
+	defer f.Close()
+
+	answers, err := packAnswers(f.Pack, sets)
+	if err != nil {
+		return err
+	}
+
+	m := packManifest(f.Pack, projectPath, answers)
+	m.Pack.Source, m.Pack.Ref = f.Lock.Source, f.Lock.Ref
+	hooks := mergeHooks(f.Hooks, extra)
+
+	if len(hooks.PreRender) > 0 {
+		if err := os.MkdirAll(projectPath, 0755); err != nil {
+			return err
+		}
+		if err := runHooks(projectPath, hookPreRender, hooks.PreRender, m); err != nil {
+			return err
+		}
+	}
+	files, err := f.Render(f.Vars(projectPath, Version, answers))
+	if err != nil {
+		return err
+	}
+	cs := newChangeSet(projectPath, m)
+	for _, out := range files {
+		cs.write(out.Path, out.Content, false)
+	}
+	if err := cs.apply(); err != nil {
+		return err
+	}
+	if err := m.Save(projectPath); err != nil {
+		return err
+	}
+	if err := pack.WriteLock(projectPath, f.Lock); err != nil {
+		return err
+	}
+	if err := runPostHooks(projectPath, hookPost, hooks.Post, m); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(projectPath, "go.mod")); err == nil {
+		fmt.Println("📦 Installing dependencies...")
+		runCommand(projectPath, "go mod tidy")
+	}
+	if err := runPostHooks(projectPath, hookPostInstall, hooks.PostInstall, m); err != nil {
+		return err
+	}
+
+	version := f.Lock.Version
+	if version == "" {
+		version = f.Lock.Commit
+	}
+	fmt.Printf("✅ Project initialized from pack %s %s\n", f.Lock.Name, version)
+	return nil
+}
+
+// packManifest returns the manifest of a project created from the pack with
+// answers. Prompts named after init's choices tell goscaf generate what the
+// project uses.
+func packManifest(p *pack.Pack, projectPath string, answers map[string]any) *manifest.Manifest {
+	choice := func(name string) string {
+		s, _ := answers[name].(string)
+		return s
+	}
+	m := &manifest.Manifest{
+		Version:   Version,
+		Module:    projectPath,
+		Framework: choice("framework"),
+		Database:  choice("database"),
+		ORM:       choice("orm"),
+		RPC:       choice("rpc"),
+		Pack: &manifest.Pack{
+			Name:    p.Name,
+			Version: p.Version,
+			Answers: answers,
+		},
+	}
+	if strings.EqualFold(m.Database, "sqlite") {
+		m.SQLiteDriver = effectiveSQLiteDriver(m.ORM, "modernc")
+	}
+	return m
+}
+
+// packAnswers returns the answer to every prompt of the pack, taken from
+// sets or asked. A select prompt with a single option is answered without
+// asking.
+func packAnswers(p *pack.Pack, sets []string) (map[string]any, error) {
+	given := map[string]string{}
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q (expected key=value)", set)
+		}
+		given[key] = value
+	}
+
+	answers := map[string]any{}
+	for _, pr := range p.Prompts {
+		if value, ok := given[pr.Name]; ok {
+			answer, err := pr.Answer(value)
+			if err != nil {
+				return nil, err
+			}
+			answers[pr.Name] = answer
+			delete(given, pr.Name)
+			continue
+		}
+		if pr.Type == pack.Select && len(pr.Options) == 1 {
+			answers[pr.Name] = pr.Options[0]
+			continue
+		}
+
+		message := pr.Message
+		if message == "" {
+			message = pr.Name + ":"
+		}
+		var err error
+		switch pr.Type {
+		case pack.Select:
+			var answer string
+			err = survey.AskOne(&survey.Select{Message: message, Options: pr.Options, Default: defaultOrNil(pr.Default)}, &answer)
+			answers[pr.Name] = answer
+		case pack.Confirm:
+			def, _ := strconv.ParseBool(pr.Default)
+			var answer bool
+			err = survey.AskOne(&survey.Confirm{Message: message, Default: def}, &answer)
+			answers[pr.Name] = answer
+		default:
+			var answer string
+			err = survey.AskOne(&survey.Input{Message: message, Default: pr.Default}, &answer)
+			answers[pr.Name] = answer
+		}
+		if err != nil {
+			fmt.Println("\nOperation canceled by user.")
+			os.Exit(1)
+		}
+	}
+	for key := range given {
+		return nil, fmt.Errorf("pack %s has no prompt %s", p.Name, key)
+	}
+	return answers, nil
+}
+
+// defaultOrNil returns def, or nil for no default, which survey.Select
+// requires.
+func defaultOrNil(def string) any {
+	if def == "" {
+		return nil
+	}
+	return def
+}
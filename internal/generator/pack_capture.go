@@ -0,0 +1,392 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/pack"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+)
+
+var packCaptureCmd = &cobra.Command{
+	Use:   "capture <project> <pack-dir>",
+	Short: "Turn an existing Go project into a template pack",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		module, _ := cmd.Flags().GetString("module")
+		name, _ := cmd.Flags().GetString("name")
+		packName, _ := cmd.Flags().GetString("pack-name")
+		replaceName, _ := cmd.Flags().GetBool("replace-name")
+
+		if err := capturePack(args[0], args[1], module, name, packName, replaceName); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	PackCmd.AddCommand(packCaptureCmd)
+	packCaptureCmd.Flags().String("module", "", "Module path to replace with {{ .Module }} (default: the module in go.mod)")
+	packCaptureCmd.Flags().String("name", "", "Project name to replace with {{ .ProjectName }} (default: the last element of the module path)")
+	packCaptureCmd.Flags().String("pack-name", "", "Name of the pack (default: the base name of <pack-dir>)")
+	packCaptureCmd.Flags().Bool("replace-name", false, "Also replace the project name in every file, not just the Dockerfile, compose file, Makefile and GoReleaser config")
+}
+
+// captureSkip are the files and directories of a project that never go
+// into a pack.
+var captureSkip = map[string]bool{
+	".git": true, "vendor": true, "node_modules": true,
+	manifest.FileName: true, pack.LockFileName: true,
+}
+
+// capturePack writes a pack to out that recreates the project at
+// projectPath: every file goes under template/, with the module path and
+// project name replaced by template variables, and the framework, database
+// and ORM its imports use become fixed prompts. Every line with a
+// replacement is printed for review.
+func capturePack(projectPath, out, module, name, packName string, replaceName bool) error {
+	if module == "" {
+		data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+		if err != nil {
+			return fmt.Errorf("could not read go.mod (pass --module): %w", err)
+		}
+		module = modfile.ModulePath(data)
+		if module == "" {
+			return fmt.Errorf("go.mod declares no module (pass --module)")
+		}
+	}
+	if name == "" {
+		name = path.Base(module)
+	}
+	if packName == "" {
+		abs, err := filepath.Abs(out)
+		if err != nil {
+			return err
+		}
+		packName = filepath.Base(abs)
+	}
+	if entries, err := os.ReadDir(out); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s already exists and is not empty", out)
+	}
+
+	files, err := projectFiles(projectPath)
+	if err != nil {
+		return err
+	}
+
+	cs := newChangeSet(out, nil)
+	var imports []string
+	templated := 0
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		dest := path.Join("template", rel)
+		if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			cs.write(dest, string(data), false) // binary
+			continue
+		}
+		content := string(data)
+		if strings.HasSuffix(rel, ".go") {
+			imports = append(imports, goImports(rel, data)...)
+		}
+		if captured, lines := captureVariables(rel, content, module, name, replaceName); len(lines) > 0 {
+			content, dest = captured, dest+".tmpl"
+			templated++
+			for _, line := range lines {
+				fmt.Printf("ℹ️  %s:%s\n", rel, line)
+			}
+		}
+		cs.write(dest, content, false)
+	}
+
+	stack := detectStack(projectPath, imports)
+	def := pack.Pack{
+		Name:        packName,
+		Version:     "0.1.0",
+		Description: "Captured from " + module,
+		Files:       []pack.File{{Src: "template", Dest: "."}},
+	}
+	for _, key := range []string{"framework", "database", "orm", "rpc"} {
+		if value, ok := stack[key]; ok {
+			def.Prompts = append(def.Prompts, pack.Prompt{Name: key, Type: pack.Select, Options: []string{value}, Default: value})
+			fmt.Printf("ℹ️  Detected %s %s\n", key, value)
+		}
+	}
+	data, err := yaml.Marshal(def)
+	if err != nil {
+		return err
+	}
+	cs.write(pack.FileName, string(data), false)
+
+	if err := cs.apply(); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Captured %d file(s), %d with template variables, into pack %s\n", len(files), templated, packName)
+	fmt.Printf("ℹ️  Create a project from it with `goscaf init <name> --pack %s`\n", out)
+	return nil
+}
+
+// projectFiles returns the project's files as slash-separated paths:
+// those Git does not ignore in a Git repository, or all of them otherwise.
+func projectFiles(projectPath string) ([]string, error) {
+	var files []string
+	if out, err := exec.Command("git", "-C", projectPath, "ls-files", "--cached", "--others", "--exclude-standard").Output(); err == nil {
+		for _, rel := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if rel == "" || captureSkip[strings.Split(rel, "/")[0]] || captureSkip[rel] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(rel))); err == nil {
+				files = append(files, rel)
+			}
+		}
+		return files, nil
+	}
+
+	err := filepath.WalkDir(projectPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != projectPath && captureSkip[d.Name()] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			rel, _ := filepath.Rel(projectPath, p)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files, err
+}
+
+// nameFile reports whether the project name is replaced in a file without
+// --replace-name: there it names the binary, the image or the compose
+// service rather than a route or some other word that happens to match.
+func nameFile(rel string) bool {
+	base := strings.ToLower(path.Base(rel))
+	switch {
+	case base == "dockerfile", strings.HasPrefix(base, "dockerfile."), strings.HasSuffix(base, ".dockerfile"):
+		return true
+	case strings.HasPrefix(base, "docker-compose.") || strings.HasPrefix(base, "compose."):
+		return true
+	case base == "makefile", base == ".goreleaser.yaml", base == ".goreleaser.yml":
+		return true
+	}
+	return false
+}
+
+// captureVariables replaces the module path and project name in content
+// with {{ .Module }} and {{ .ProjectName }}, escaping any template actions
+// already there. The module path is only replaced in the module directive
+// of go.mod, in Go import paths and in the go_package option of protobuf
+// files. The name is replaced in the files
+// nameFile picks out or, with replaceName, everywhere; in Go files only in
+// strings and comments, where it cannot be an identifier. It returns every
+// changed line, numbered, for review.
+func captureVariables(rel, content, module, name string, replaceName bool) (string, []string) {
+	const (
+		moduleMark = "\x00module\x00"
+		nameMark   = "\x00name\x00"
+	)
+	nameRE := regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(name) + `($|[^\w-])`)
+	replace := func(s string) string {
+		// Twice, so adjacent matches sharing a delimiter are all replaced.
+		for range 2 {
+			s = nameRE.ReplaceAllString(s, "${1}"+nameMark+"${2}")
+		}
+		return s
+	}
+
+	marked := content
+	switch {
+	case rel == "go.mod":
+		moduleRE := regexp.MustCompile(`(?m)^(module\s+"?)` + regexp.QuoteMeta(module) + `("?\s*(//.*)?)$`)
+		marked = moduleRE.ReplaceAllString(content, "${1}"+moduleMark+"${2}")
+	case strings.HasSuffix(rel, ".proto"):
+		goPackageRE := regexp.MustCompile(`(?m)^(\s*option\s+go_package\s*=\s*")` + regexp.QuoteMeta(module) + `([/;"])`)
+		marked = goPackageRE.ReplaceAllString(content, "${1}"+moduleMark+"${2}")
+		if replaceName {
+			marked = replace(marked)
+		}
+	case strings.HasSuffix(rel, ".go"):
+		imports := map[int]bool{}
+		if f, err := parser.ParseFile(token.NewFileSet(), rel, content, parser.ImportsOnly); err == nil {
+			for _, imp := range f.Imports {
+				imports[int(imp.Path.Pos())-1] = true // the file's base is 1
+			}
+		}
+		marked = replaceInLiterals(content, func(offset int, lit string) string {
+			if !imports[offset] {
+				if replaceName {
+					return replace(lit)
+				}
+				return lit
+			}
+			if p, err := strconv.Unquote(lit); err == nil && (p == module || strings.HasPrefix(p, module+"/")) {
+				return lit[:1] + moduleMark + lit[1+len(module):]
+			}
+			return lit
+		})
+	case rel == "go.sum":
+		// Checksums of modules whose path happens to contain the name.
+	case replaceName || nameFile(rel):
+		marked = replace(content)
+	}
+	if marked == content {
+		return content, nil
+	}
+
+	var lines []string
+	before := strings.Split(content, "\n")
+	for i, line := range strings.Split(marked, "\n") {
+		if line != before[i] {
+			line = strings.ReplaceAll(line, moduleMark, "{{ .Module }}")
+			line = strings.ReplaceAll(line, nameMark, "{{ .ProjectName }}")
+			lines = append(lines, fmt.Sprintf("%d: %s", i+1, strings.TrimSpace(line)))
+		}
+	}
+	marked = strings.ReplaceAll(marked, "{{", `{{"{{"}}`)
+	marked = strings.ReplaceAll(marked, moduleMark, "{{ .Module }}")
+	return strings.ReplaceAll(marked, nameMark, "{{ .ProjectName }}"), lines
+}
+
+// replaceInLiterals applies replace to the string literals and comments of
+// Go source, given with their byte offset, leaving the code between them
+// alone. Source that does not scan is returned unchanged.
+func replaceInLiterals(src string, replace func(offset int, lit string) string) string {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	failed := false
+	s.Init(file, []byte(src), func(token.Position, string) { failed = true }, scanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.STRING && tok != token.COMMENT && tok != token.CHAR {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		b.WriteString(src[last:start])
+		b.WriteString(replace(start, src[start:end]))
+		last = end
+	}
+	if failed {
+		return src
+	}
+	b.WriteString(src[last:])
+	return b.String()
+}
+
+// goImports returns the import paths of a Go file.
+func goImports(rel string, src []byte) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), rel, src, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, imp := range f.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// detectStack maps the imports of a project to the framework, database,
+// ORM and RPC option of the registry that use them.
+func detectStack(projectPath string, imports []string) map[string]string {
+	imported := func(modules ...string) bool {
+		for _, module := range modules {
+			for _, imp := range imports {
+				if imp == module || strings.HasPrefix(imp, module+"/") {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	stack := map[string]string{}
+	for _, fw := range Frameworks {
+		if modules, _ := frameworkModules(fw.Name); imported(modules...) {
+			stack["framework"] = fw.Name
+			break
+		}
+	}
+	for _, db := range Databases {
+		// A database shows in its driver, or in the dialect of an ORM.
+		var modules []string
+		for _, driver := range []string{"modernc", "mattn"} {
+			mods, _ := driverModules(db.Name, driver)
+			modules = append(modules, mods...)
+			for _, orm := range []string{"GORM", "Bun"} {
+				if mods, err := ormModules(orm, db.Name, driver); err == nil && len(mods) > 1 {
+					modules = append(modules, mods[1:]...)
+				}
+			}
+		}
+		if imported(modules...) {
+			stack["database"] = db.Name
+			break
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "sqlc.yaml")); err == nil {
+		stack["orm"] = "sqlc"
+	} else {
+		// Bun comes before sqlx/pgx, which shares its Postgres driver.
+		for _, orm := range ORMs {
+			if orm.Status == Unsupported {
+				continue
+			}
+			var modules []string
+			for _, db := range orm.Databases {
+				mods, _ := ormModules(orm.Name, db, "modernc")
+				if orm.Name != "sqlx/pgx" && len(mods) > 0 {
+					mods = mods[:1]
+				}
+				modules = append(modules, mods...)
+			}
+			if imported(modules...) {
+				stack["orm"] = orm.Name
+				break
+			}
+		}
+	}
+	if _, ok := stack["database"]; ok {
+		if _, ok := stack["orm"]; !ok {
+			stack["orm"] = "none"
+		}
+	}
+	for _, rpc := range []string{"Connect-RPC", "gRPC-Gateway"} {
+		if imported(rpcModules(strings.ToLower(rpc))[0]) {
+			stack["rpc"] = rpc
+			break
+		}
+	}
+	return stack
+}
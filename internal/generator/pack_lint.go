@@ -0,0 +1,178 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/pack"
+	"github.com/spf13/cobra"
+)
+
+var packLintCmd = &cobra.Command{
+	Use:   "lint [pack-dir]",
+	Short: "Check a pack's definition and templates",
+	Long: "Validates " + pack.FileName + ", checks that every template parses and only uses defined\n" +
+		"variables, and renders every combination of select and confirm answers to check that\n" +
+		"conditional files cover them and that the Go files rendered parse and are gofmt-clean.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := pack.Load(packDir(args))
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		problems := 0
+		for _, issue := range p.Lint() {
+			if issue.Warning {
+				fmt.Println("⚠️  " + issue.String())
+				continue
+			}
+			fmt.Println("❌ " + issue.String())
+			problems++
+		}
+		if problems > 0 {
+			fmt.Printf("❌ %d problem(s) found\n", problems)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ No problems found in pack %s\n", p.Name)
+	},
+}
+
+var packTestCmd = &cobra.Command{
+	Use:   "test [pack-dir]",
+	Short: "Render a pack's test matrix and type-check every sample",
+	Long: "Renders a project for every sample of the test matrix in " + pack.FileName + ", or for the\n" +
+		"default answers if there is none, the way init would, hooks included, and type-checks\n" +
+		"it with go vet.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keep, _ := cmd.Flags().GetBool("keep")
+
+		p, err := pack.Load(packDir(args))
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(p.Test.Matrix) == 0 {
+			fmt.Println("ℹ️  No test matrix in " + pack.FileName + "; testing the default answers")
+		}
+
+		samples := p.Samples()
+		failed := 0
+		for _, answers := range samples {
+			if !testSample(p, answers, keep) {
+				failed++
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("❌ %d of %d sample(s) failed\n", failed, len(samples))
+			os.Exit(1)
+		}
+		fmt.Printf("✅ All %d sample(s) passed\n", len(samples))
+	},
+}
+
+func init() {
+	PackCmd.AddCommand(packLintCmd)
+	PackCmd.AddCommand(packTestCmd)
+	packTestCmd.Flags().Bool("keep", false, "Keep the rendered projects of passing samples too")
+}
+
+func packDir(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	return "."
+}
+
+// testSample creates a temporary project from the pack with answers the way
+// init does, hooks included, and type-checks it with go vet. The project of
+// a failed sample is kept for inspection.
+func testSample(p *pack.Pack, answers map[string]any, keep bool) bool {
+	sample := p.Describe(answers)
+	tmp, err := os.MkdirTemp("", "goscaf-pack-test-")
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", sample, err)
+		return false
+	}
+	projectPath := filepath.Join(tmp, "example")
+
+	fail := func(step string, output string) bool {
+		fmt.Printf("❌ %s: %s\n", sample, step)
+		if output = strings.TrimSpace(output); output != "" {
+			fmt.Println("   " + strings.ReplaceAll(output, "\n", "\n   "))
+		}
+		fmt.Println("ℹ️  The project is in " + projectPath)
+		return false
+	}
+
+	m := packManifest(p, filepath.Base(projectPath), answers)
+	hooks := func(stage string, commands []string) bool {
+		env := append(os.Environ(), hookEnv(projectPath, stage, m)...)
+		for _, hook := range commands {
+			cmd := exec.Command("sh", "-c", hook)
+			cmd.Dir, cmd.Env = projectPath, env
+			if output, err := cmd.CombinedOutput(); err != nil {
+				return fail(fmt.Sprintf("%s hook %q failed", stage, hook), string(output))
+			}
+		}
+		return true
+	}
+
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		return fail("writing failed", err.Error())
+	}
+	if !hooks(hookPreRender, p.Hooks.PreRender) {
+		return false
+	}
+	outputs, err := p.Render(p.Vars(filepath.Base(projectPath), Version, answers))
+	if err != nil {
+		return fail("rendering failed", err.Error())
+	}
+	for _, out := range outputs {
+		file := filepath.Join(projectPath, filepath.FromSlash(out.Path))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fail("writing failed", err.Error())
+		}
+		if err := os.WriteFile(file, []byte(out.Content), 0644); err != nil {
+			return fail("writing failed", err.Error())
+		}
+	}
+	if !hooks(hookPost, p.Hooks.Post) {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "go.mod")); err == nil {
+		if output, err := runQuiet(projectPath, "go", "mod", "tidy"); err != nil {
+			return fail("installing dependencies failed", output)
+		}
+	}
+	if !hooks(hookPostInstall, p.Hooks.PostInstall) {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "go.mod")); err != nil {
+		fmt.Printf("⚠️  %s: no go.mod, nothing to type-check\n", sample)
+	} else if output, err := runQuiet(projectPath, "go", "vet", "./..."); err != nil {
+		return fail("go vet failed", output)
+	} else {
+		fmt.Printf("✅ %s\n", sample)
+	}
+
+	if keep {
+		fmt.Println("ℹ️  The project is in " + projectPath)
+	} else {
+		os.RemoveAll(tmp)
+	}
+	return true
+}
+
+// runQuiet runs a command in dir and returns its combined output.
+func runQuiet(dir, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/astedit"
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// AddPlugins adds a subcommand to root for each goscaf-<name> executable on
+// PATH whose name no built-in command uses.
+func AddPlugins(root *cobra.Command) {
+	taken := map[string]bool{"help": true, "completion": true}
+	for _, c := range root.Commands() {
+		taken[c.Name()] = true
+	}
+	plugins := plugin.Discover()
+	if len(plugins) == 0 {
+		return
+	}
+	root.AddGroup(&cobra.Group{ID: "plugins", Title: "Plugin Commands:"})
+	for _, p := range plugins {
+		if taken[p.Name] {
+			continue
+		}
+		root.AddCommand(pluginCmd(p))
+	}
+}
+
+// pluginCmd runs a plugin. Every argument goes to the plugin except --dir
+// and --dry-run, which goscaf handles.
+func pluginCmd(p plugin.Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              "Plugin at " + p.Path,
+		GroupID:            "plugins",
+		DisableFlagParsing: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			projectPath, dryRun, args := pluginFlags(args)
+			if err := runPlugin(p, projectPath, args, dryRun); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+// pluginFlags takes goscaf's --dir and --dry-run out of a plugin's
+// arguments, up to a "--".
+func pluginFlags(args []string) (projectPath string, dryRun bool, rest []string) {
+	projectPath, rest = ".", []string{}
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--":
+			return projectPath, dryRun, append(rest, args[i:]...)
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--dir" && i+1 < len(args):
+			projectPath = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--dir="):
+			projectPath = strings.TrimPrefix(arg, "--dir=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return projectPath, dryRun, rest
+}
+
+// runPlugin runs a plugin for the project and applies its response through
+// a change set.
+func runPlugin(p plugin.Plugin, projectPath string, args []string, dryRun bool) error {
+	projectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return err
+	}
+	m, err := manifest.Load(projectPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", manifest.FileName, err)
+	}
+
+	resp, err := p.Run(plugin.Request{
+		Protocol:      plugin.ProtocolVersion,
+		GoscafVersion: Version,
+		Args:          args,
+		ProjectRoot:   projectPath,
+		Manifest:      m,
+		DryRun:        dryRun,
+	}, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	cs := newChangeSet(projectPath, m)
+	for _, f := range resp.Files {
+		cs.write(f.Path, f.Content, f.Overwrite)
+	}
+	for _, e := range resp.Edits {
+		edit, err := pluginEdit(e)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", p.Name, err)
+		}
+		cs.editGo(e.Path, edit)
+	}
+
+	if dryRun {
+		return cs.preview()
+	}
+	if err := cs.apply(); err != nil {
+		return err
+	}
+	if m != nil {
+		return m.Save(projectPath)
+	}
+	return nil
+}
+
+// pluginEdit returns the astedit operation an edit of a plugin response
+// asks for.
+func pluginEdit(e plugin.Edit) (func(*astedit.File) (bool, error), error) {
+	switch e.Op {
+	case plugin.AppendStmt:
+		if e.Func == "" || e.Code == "" {
+			return nil, fmt.Errorf("%s of %s needs func and code", e.Op, e.Path)
+		}
+		return func(f *astedit.File) (bool, error) { return f.AppendStmt(e.Func, e.Code) }, nil
+	case plugin.RemoveCalls:
+		if e.Func == "" || e.Code == "" {
+			return nil, fmt.Errorf("%s of %s needs func and code", e.Op, e.Path)
+		}
+		return func(f *astedit.File) (bool, error) {
+			n, err := f.RemoveCalls(e.Func, e.Code)
+			return n > 0, err
+		}, nil
+	case plugin.AppendDecl:
+		if e.Code == "" {
+			return nil, fmt.Errorf("%s of %s needs code", e.Op, e.Path)
+		}
+		return func(f *astedit.File) (bool, error) { return f.AppendDecl(e.Code) }, nil
+	case plugin.AddImports:
+		specs := make([]string, len(e.Imports))
+		for i, spec := range e.Imports {
+			if !strings.Contains(spec, `"`) {
+				spec = strconv.Quote(spec)
+			}
+			specs[i] = spec
+		}
+		return func(f *astedit.File) (bool, error) { return f.AddImports(specs...) }, nil
+	}
+	return nil, fmt.Errorf("unknown edit op %q for %s", e.Op, e.Path)
+}
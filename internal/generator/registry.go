@@ -0,0 +1,243 @@
+package generator
+
+import "strings"
+
+// Status is how well goscaf supports a framework, database, ORM or feature,
+// or a combination of them.
+type Status string
+
+const (
+	Supported    Status = "supported"
+	Experimental Status = "experimental"
+	Unsupported  Status = "unsupported"
+)
+
+// Framework describes a web framework offered by the init prompt.
+type Framework struct {
+	Name   string
+	Status Status
+}
+
+// Database describes a database system offered by the init prompt.
+type Database struct {
+	Name        string
+	SQL         bool
+	DefaultPort string
+	Status      Status
+	Note        string
+}
+
+// ORM describes a data-access option and the databases it can target.
+// Unsupported ORMs are listed but not offered by the init prompt.
+type ORM struct {
+	Name      string
+	Databases []string
+	Status    Status
+	Note      string
+}
+
+// Feature describes something goscaf generates beyond the base project.
+// Supports reports whether it applies to a database and ORM; nil means it
+// always does.
+type Feature struct {
+	Name        string
+	Description string
+	Status      Status
+	Note        string
+	Supports    func(database, orm string) bool
+}
+
+var Frameworks = []Framework{
+	{Name: "Fiber", Status: Supported},
+	{Name: "Gin", Status: Supported},
+	{Name: "Echo", Status: Supported},
+	{Name: "Chi", Status: Supported},
+	{Name: "Iris", Status: Supported},
+}
+
+var Databases = []Database{
+	{Name: "Postgres", SQL: true, DefaultPort: "5432", Status: Supported},
+	{Name: "MySQL", SQL: true, DefaultPort: "3306", Status: Supported},
+	{Name: "SQLite", SQL: true, Status: Supported, Note: "modernc by default; mattn with --sqlite-driver mattn, which needs cgo"},
+	{Name: "SQLServer", SQL: true, DefaultPort: "1433", Status: Supported},
+	{Name: "CockroachDB", SQL: true, DefaultPort: "26257", Status: Supported},
+	{Name: "MongoDB", DefaultPort: "27017", Status: Supported, Note: "no ORM; resources skip unique, index and default"},
+}
+
+var ORMs = []ORM{
+	{Name: "GORM", Databases: []string{"Postgres", "MySQL", "SQLite", "SQLServer", "CockroachDB"}, Status: Supported},
+	{Name: "XORM", Databases: []string{"Postgres", "MySQL", "SQLite", "SQLServer", "CockroachDB"}, Status: Supported},
+	{Name: "Ent", Databases: []string{"Postgres", "MySQL", "SQLite", "CockroachDB"}, Status: Supported,
+		Note: "manages its own schema instead of SQL migrations"},
+	{Name: "Bun", Databases: []string{"Postgres", "MySQL", "SQLite", "SQLServer", "CockroachDB"}, Status: Supported},
+	{Name: "sqlx/pgx", Databases: []string{"Postgres", "MySQL", "SQLite", "SQLServer", "CockroachDB"}, Status: Supported},
+	{Name: "sqlc", Databases: []string{"Postgres", "MySQL", "SQLite", "CockroachDB"}, Status: Supported,
+		Note: "needs the sqlc tool to run `go generate`"},
+	{Name: "SQLBoiler", Databases: []string{"Postgres", "MySQL", "SQLite"}, Status: Unsupported,
+		Note: "no database setup is generated for it yet"},
+}
+
+var Features = []Feature{
+	{Name: "connect-rpc", Description: "Connect-RPC server mounted on the router", Status: Supported,
+		Note: "needs buf to generate the stubs"},
+	{Name: "grpc-gateway", Description: "gRPC server with a JSON reverse proxy", Status: Supported,
+		Note: "needs buf to generate the stubs"},
+	{Name: "migrations", Description: "Numbered SQL migrations applied at startup and by goscaf migrate", Status: Supported,
+		Supports: usesMigrations},
+	{Name: "resources", Description: "goscaf generate resource", Status: Supported},
+	{Name: "from-sql", Description: "goscaf generate from-sql", Status: Supported, Supports: sqlDatabase},
+	{Name: "from-db", Description: "goscaf generate from-db", Status: Experimental,
+		Note: "introspects SQLite and Postgres only", Supports: sqlDatabase},
+	{Name: "from-openapi", Description: "goscaf generate from-openapi", Status: Supported},
+	{Name: "docs", Description: "OpenAPI document and Swagger UI at /docs", Status: Supported},
+}
+
+// pins are the versions init installs. They are the versions the templates
+// were last checked against; go mod tidy may still raise them to satisfy
+// other requirements.
+var pins = map[string]string{
+	"github.com/joho/godotenv": "v1.5.1",
+	"golang.org/x/crypto":      "v0.57.0",
+	// A pin that fixes missing dependencies.
+	"github.com/mattn/go-isatty": "v0.0.20",
+
+	"github.com/gofiber/fiber/v3": "v3.1.0",
+	"github.com/gin-gonic/gin":    "v1.12.0",
+	"github.com/labstack/echo/v4": "v4.16.0",
+	"github.com/go-chi/chi/v5":    "v5.3.2",
+	"github.com/kataras/iris/v12": "v12.2.11",
+
+	"github.com/lib/pq":               "v1.12.3",
+	"github.com/go-sql-driver/mysql":  "v1.10.1",
+	"modernc.org/sqlite":              "v1.60.1",
+	"github.com/mattn/go-sqlite3":     "v1.14.52",
+	"github.com/microsoft/go-mssqldb": "v1.11.2",
+	"go.mongodb.org/mongo-driver/v2":  "v2.9.1",
+
+	"gorm.io/gorm":                                 "v1.31.2",
+	"gorm.io/driver/postgres":                      "v1.6.3",
+	"gorm.io/driver/mysql":                         "v1.6.0",
+	"gorm.io/driver/sqlite":                        "v1.6.0",
+	"gorm.io/driver/sqlserver":                     "v1.6.3",
+	"github.com/glebarez/sqlite":                   "v1.11.0",
+	"xorm.io/xorm":                                 "v1.4.3",
+	"entgo.io/ent":                                 "v0.14.5",
+	"github.com/uptrace/bun":                       "v1.2.18",
+	"github.com/uptrace/bun/dialect/pgdialect":     "v1.2.18",
+	"github.com/uptrace/bun/dialect/mysqldialect":  "v1.2.18",
+	"github.com/uptrace/bun/dialect/sqlitedialect": "v1.2.18",
+	"github.com/uptrace/bun/dialect/mssqldialect":  "v1.2.18",
+	"github.com/jackc/pgx/v5":                      "v5.11.0",
+	"github.com/jmoiron/sqlx":                      "v1.4.0",
+	"github.com/golang-migrate/migrate/v4":         "v4.20.1",
+	"github.com/pressly/goose/v3":                  "v3.28.0",
+
+	"connectrpc.com/connect":                    "v1.21.0",
+	"google.golang.org/protobuf":                "v1.36.12",
+	"github.com/grpc-ecosystem/grpc-gateway/v2": "v2.31.0",
+	"github.com/improbable-eng/grpc-web":        "v0.15.0",
+	"google.golang.org/grpc":                    "v1.84.0",
+}
+
+// pinned returns module with the version init installs appended, or
+// unchanged if it has none.
+func pinned(module string) string {
+	if version, ok := pins[module]; ok {
+		return module + "@" + version
+	}
+	return module
+}
+
+func frameworkNames() []string {
+	names := make([]string, 0, len(Frameworks))
+	for _, fw := range Frameworks {
+		names = append(names, fw.Name)
+	}
+	return names
+}
+
+func findFramework(name string) (Framework, bool) {
+	for _, fw := range Frameworks {
+		if strings.EqualFold(fw.Name, name) {
+			return fw, true
+		}
+	}
+	return Framework{}, false
+}
+
+func findORM(name string) (ORM, bool) {
+	for _, orm := range ORMs {
+		if strings.EqualFold(orm.Name, name) {
+			return orm, true
+		}
+	}
+	return ORM{}, false
+}
+
+func databaseNames() []string {
+	names := make([]string, 0, len(Databases))
+	for _, db := range Databases {
+		names = append(names, db.Name)
+	}
+	return names
+}
+
+func findDatabase(name string) (Database, bool) {
+	for _, db := range Databases {
+		if strings.EqualFold(db.Name, name) {
+			return db, true
+		}
+	}
+	return Database{}, false
+}
+
+// ormNamesFor returns the ORMs the init prompt offers for the given
+// database: those that can target it and are not unsupported.
+func ormNamesFor(database string) []string {
+	var names []string
+	for _, orm := range ORMs {
+		if orm.Status != Unsupported && orm.targets(database) {
+			names = append(names, orm.Name)
+		}
+	}
+	return names
+}
+
+func (orm ORM) targets(database string) bool {
+	for _, db := range orm.Databases {
+		if strings.EqualFold(db, database) {
+			return true
+		}
+	}
+	return false
+}
+
+func sqlDatabase(database, orm string) bool {
+	db, ok := findDatabase(database)
+	return ok && db.SQL
+}
+
+// compatibility returns the status of a framework, database and ORM
+// combination, and why it is not supported if it is not. orm is "none"
+// for plain database access.
+func compatibility(framework, database, orm string) (Status, string) {
+	if _, ok := findFramework(framework); !ok {
+		return Unsupported, "unknown framework " + framework
+	}
+	if _, ok := findDatabase(database); !ok {
+		return Unsupported, "unknown database " + database
+	}
+	if strings.EqualFold(orm, "none") {
+		return Supported, ""
+	}
+	o, ok := findORM(orm)
+	switch {
+	case !ok:
+		return Unsupported, "unknown ORM " + orm
+	case !o.targets(database):
+		return Unsupported, o.Name + " cannot target " + database
+	case strings.EqualFold(database, "sqlite") && effectiveSQLiteDriver(orm, "modernc") == "mattn":
+		return o.Status, strings.TrimPrefix(o.Note+"; uses the mattn SQLite driver, which needs cgo", "; ")
+	}
+	return o.Status, o.Note
+}
@@ -0,0 +1,371 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/regions"
+	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/samznd/goscaf/pkg/utils"
+)
+
+// specTypes maps Go field types back to the types parseFields accepts.
+var specTypes = map[string]string{
+	"string": "string", "int": "int", "int64": "int64", "float64": "float",
+	"bool": "bool", "time.Time": "time", "uuid.UUID": "uuid",
+	"json.RawMessage": "json", "[]byte": "bytes", "decimal.Decimal": "decimal",
+	"bson.ObjectID": "objectid",
+}
+
+// fieldSpec writes a field in the syntax parseFields reads. The manifest
+// stores resources this way so they can be rendered again when a relation
+// is added.
+func fieldSpec(f templates.Field) string {
+	name, opts := f.Column, []string{}
+	if utils.ToSnake(f.Column) != f.Column || utils.ToPascal(f.Column) != f.Name {
+		name, opts = f.Name, append(opts, "column="+f.Column)
+	}
+	if f.Nullable {
+		opts = append(opts, "null")
+	}
+	if f.PrimaryKey {
+		opts = append(opts, "pk")
+	}
+	if f.AutoIncrement {
+		opts = append(opts, "auto")
+	}
+	if f.Unique {
+		opts = append(opts, "unique")
+	}
+	if f.Index {
+		opts = append(opts, "index")
+	}
+	if f.Default != "" {
+		opts = append(opts, "default="+f.Default)
+	}
+	if f.Size > 0 {
+		opts = append(opts, "size="+strconv.Itoa(f.Size))
+	}
+	if f.References != "" {
+		opts = append(opts, "ref="+f.References)
+	}
+	for _, rule := range strings.Split(f.Validate, ",") {
+		if rule != "" {
+			opts = append(opts, strings.ReplaceAll(rule, " ", "|"))
+		}
+	}
+	return strings.Join(append([]string{name, specTypes[f.Type]}, opts...), ":")
+}
+
+func fieldSpecs(fields []templates.Field) []string {
+	specs := make([]string, len(fields))
+	for i, f := range fields {
+		specs[i] = fieldSpec(f)
+	}
+	return specs
+}
+
+// loadResource rebuilds a generated resource, without its relations, from
+// the field specs in the manifest.
+func loadResource(m *manifest.Manifest, res manifest.Resource) (templates.Resource, error) {
+	if len(res.Fields) == 0 {
+		return templates.Resource{}, fmt.Errorf("%s was generated before goscaf recorded resource fields and cannot be related", res.Name)
+	}
+	fields, err := parseFields(res.Fields)
+	if err != nil {
+		return templates.Resource{}, fmt.Errorf("%s: %w", res.Name, err)
+	}
+	return newResource(m, res.Name, res.Table, fields), nil
+}
+
+// relations resolves the relations of r: one belongs-to per parent and one
+// has-many per resource that belongs to r.
+func relations(m *manifest.Manifest, r templates.Resource, belongsTo []string) ([]templates.Relation, error) {
+	var rels []templates.Relation
+	for _, name := range belongsTo {
+		i := m.FindResource(name)
+		if i < 0 {
+			return nil, fmt.Errorf("resource %s does not exist", name)
+		}
+		parent, err := loadResource(m, m.Resources[i])
+		if err != nil {
+			return nil, err
+		}
+		fk, ok := foreignKey(r, parent)
+		if !ok {
+			return nil, fmt.Errorf("%s has no foreign key to %s", r.Name, parent.Table)
+		}
+		rels = append(rels, templates.Relation{Kind: templates.BelongsTo, Name: parent.Name, Table: parent.Table, FK: fk, PK: parent.PK(), IDType: parent.IDType()})
+	}
+	for _, res := range m.Resources {
+		if res.Name == r.Name || !slices.Contains(res.BelongsTo, r.Name) {
+			continue
+		}
+		child, err := loadResource(m, res)
+		if err != nil {
+			return nil, err
+		}
+		fk, ok := foreignKey(child, r)
+		if !ok {
+			return nil, fmt.Errorf("%s has no foreign key to %s", child.Name, r.Table)
+		}
+		rels = append(rels, templates.Relation{Kind: templates.HasMany, Name: child.Name, Table: child.Table, FK: fk, PK: r.PK(), IDType: r.IDType()})
+	}
+	return rels, nil
+}
+
+// foreignKey returns the field of child that references parent's primary key.
+func foreignKey(child, parent templates.Resource) (templates.Field, bool) {
+	ref := parent.Table + "." + parent.PK().Column
+	for _, f := range child.Fields {
+		if f.References == ref {
+			return f, true
+		}
+	}
+	return templates.Field{}, false
+}
+
+// foreignKeyField is the column a new belongs-to relation adds, e.g. user_id
+// referencing users.id.
+func foreignKeyField(parent templates.Resource) templates.Field {
+	pk := parent.PK()
+	column := utils.ToSnake(parent.Name) + "_" + strings.TrimPrefix(pk.Column, "_")
+	return templates.Field{
+		Name:       utils.ToPascal(column),
+		Column:     column,
+		Type:       pk.Type,
+		References: parent.Table + "." + pk.Column,
+		Index:      true,
+	}
+}
+
+// addForeignKey makes sure r has a foreign key to parent, reusing a field of
+// the same name if the user declared one.
+func addForeignKey(r *templates.Resource, parent templates.Resource) {
+	if _, ok := foreignKey(*r, parent); ok {
+		return
+	}
+	fk := foreignKeyField(parent)
+	for i, f := range r.Fields {
+		if f.Column == fk.Column {
+			r.Fields[i].References = fk.References
+			r.Fields[i].Index = r.Fields[i].Index || !f.Unique
+			return
+		}
+	}
+	r.Fields = append(r.Fields, fk)
+}
+
+// resourceName normalizes a resource name given on the command line.
+func resourceName(arg string) string {
+	return utils.ToPascal(utils.Singularize(utils.ToSnake(arg)))
+}
+
+// linkResource resolves the relations requested for the new resource r
+// before it is written. It adds a foreign key to r for each parent and
+// returns the parents, the children that already exist and the children
+// that do not exist yet. Resources that declared r as a has-many child
+// before it existed become parents as well.
+func linkResource(m *manifest.Manifest, r *templates.Resource, belongsTo, hasMany []string) (parents, children, pending []string, err error) {
+	for _, res := range m.Resources {
+		if slices.Contains(res.HasMany, r.Name) && !slices.Contains(belongsTo, res.Name) {
+			belongsTo = append(belongsTo, res.Name)
+		}
+	}
+	for _, arg := range belongsTo {
+		name := resourceName(arg)
+		if name == r.Name {
+			return nil, nil, nil, fmt.Errorf("%s cannot belong to itself", r.Name)
+		}
+		i := m.FindResource(name)
+		if i < 0 {
+			return nil, nil, nil, fmt.Errorf("resource %s does not exist; generate it first or declare %s with --has-many %s", name, name, r.Name)
+		}
+		parent, err := loadResource(m, m.Resources[i])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !slices.Contains(parents, parent.Name) {
+			addForeignKey(r, parent)
+			parents = append(parents, parent.Name)
+		}
+	}
+
+	for _, arg := range hasMany {
+		name := resourceName(arg)
+		if name == r.Name {
+			return nil, nil, nil, fmt.Errorf("%s cannot have many of itself", r.Name)
+		}
+		i := m.FindResource(name)
+		if i < 0 {
+			pending = append(pending, name)
+			continue
+		}
+		if _, err := loadResource(m, m.Resources[i]); err != nil {
+			return nil, nil, nil, err
+		}
+		if slices.Contains(parents, m.Resources[i].Name) {
+			return nil, nil, nil, fmt.Errorf("%s cannot both belong to and have many %s", r.Name, name)
+		}
+		children = append(children, m.Resources[i].Name)
+	}
+	return parents, children, pending, nil
+}
+
+// adoptChild records that an existing child belongs to parent. If the child
+// has no foreign key to parent yet, a nullable one is added to its fields and
+// returned so the caller can migrate it once the parent table exists.
+func adoptChild(m *manifest.Manifest, parent templates.Resource, name string) (*templates.Field, error) {
+	i := m.FindResource(name)
+	child, err := loadResource(m, m.Resources[i])
+	if err != nil {
+		return nil, err
+	}
+	res := &m.Resources[i]
+	if !slices.Contains(res.BelongsTo, parent.Name) {
+		res.BelongsTo = append(res.BelongsTo, parent.Name)
+	}
+	if _, ok := foreignKey(child, parent); ok {
+		return nil, nil
+	}
+	fk := foreignKeyField(parent)
+	fk.Nullable = true
+	for _, f := range child.Fields {
+		if f.Column == fk.Column {
+			return nil, fmt.Errorf("%s already has a %s column", child.Name, fk.Column)
+		}
+	}
+	res.Fields = append(res.Fields, fieldSpec(fk))
+	return &fk, nil
+}
+
+// migrateForeignKey adds the migration for a foreign key column added to an
+// existing resource. The migration belongs to that resource.
+func migrateForeignKey(projectPath string, m *manifest.Manifest, name string, fk templates.Field) error {
+	res := &m.Resources[m.FindResource(name)]
+	child, err := loadResource(m, *res)
+	if err != nil {
+		return err
+	}
+	up, down := templates.ResourceForeignKeyMigration(child, fk)
+	written, err := appendMigration(projectPath, m, "add_"+fk.Column+"_to_"+child.Table, up, down)
+	if err != nil {
+		return err
+	}
+	for _, path := range written {
+		rel, _ := filepath.Rel(projectPath, path)
+		res.Files = append(res.Files, filepath.ToSlash(rel))
+	}
+	return nil
+}
+
+// resourceFile is a file written for a resource, relative to the project.
+type resourceFile struct{ name, content string }
+
+// resourceFiles renders the files owned by a resource.
+func resourceFiles(r templates.Resource) []resourceFile {
+	base := r.FileName()
+	files := []resourceFile{
+		{"internal/models/" + base + ".go", templates.ResourceModelTemplate(r)},
+		{"internal/repositories/" + base + "_repository.go", templates.ResourceRepositoryTemplate(r)},
+		{"internal/services/" + base + "_service.go", templates.ResourceServiceTemplate(r)},
+		{"internal/handlers/" + base + "_handler.go", templates.ResourceHandlerTemplate(r)},
+		{"internal/routes/" + base + "_routes.go", templates.ResourceRoutesTemplate(r)},
+	}
+	if r.ORM == "ent" {
+		files = append(files, resourceFile{"ent/schema/" + base + ".go", templates.ResourceEntSchemaTemplate(r)})
+	}
+	for i := range files {
+		files[i].content = templates.Generated(files[i].content)
+	}
+	return files
+}
+
+// sharedResourceFiles renders the helpers r needs that are shared by all
+// resources. They are written once and are not owned by any single resource.
+func sharedResourceFiles(r templates.Resource) []resourceFile {
+	files := []resourceFile{
+		{"internal/repositories/errors.go", templates.ResourceErrorsTemplate()},
+	}
+	if r.Framework == "chi" {
+		files = append(files, resourceFile{"internal/handlers/json.go", templates.ResourceJSONHelperTemplate()})
+	}
+	if r.Validated() {
+		files = append(files, resourceFile{"internal/handlers/validate.go", templates.ResourceValidatorTemplate()})
+	}
+	return files
+}
+
+// writeResourceFile formats and writes a rendered resource file, keeping
+// the custom regions of the file it replaces.
+func writeResourceFile(projectPath string, f resourceFile) (string, error) {
+	path := filepath.Join(projectPath, f.name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	content, err := preserveRegions(path, f.content)
+	if err != nil {
+		return "", err
+	}
+	if formatted, err := format.Source([]byte(content)); err == nil {
+		content = string(formatted)
+	}
+	return path, utils.CreateFile(path, content)
+}
+
+// rerenderResource renders an existing resource again with its current
+// relations. Files edited since goscaf wrote them are left alone and
+// reported, since the relation has to be added to them by hand.
+func rerenderResource(projectPath string, m *manifest.Manifest, name string) error {
+	res := m.Resources[m.FindResource(name)]
+	r, err := loadResource(m, res)
+	if err != nil {
+		return err
+	}
+	if r.Relations, err = relations(m, r, res.BelongsTo); err != nil {
+		return err
+	}
+	for _, f := range resourceFiles(r) {
+		if m.Modified(projectPath, f.name) {
+			fmt.Printf("⚠️  %s has local changes; update it for the relations of %s yourself\n", f.name, r.Name)
+			continue
+		}
+		path, err := writeResourceFile(projectPath, f)
+		if errors.Is(err, errRegionsLost) {
+			fmt.Printf("⚠️  %s: %v; update it for the relations of %s yourself\n", f.name, err, r.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := m.Track(projectPath, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errRegionsLost means a file has custom regions the new version of it has
+// no place for.
+var errRegionsLost = errors.New("custom regions would be lost")
+
+// preserveRegions carries the custom regions of the file at path, if it
+// exists, over to its new content.
+func preserveRegions(path, content string) (string, error) {
+	old, err := os.ReadFile(path)
+	if err != nil {
+		return content, nil
+	}
+	content, lost := regions.Restore(content, string(old))
+	if len(lost) > 0 {
+		return "", fmt.Errorf("%w: %s", errRegionsLost, strings.Join(lost, ", "))
+	}
+	return content, nil
+}
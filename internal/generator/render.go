@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/openapi"
+	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/samznd/goscaf/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// RenderCmd writes the files goscaf would generate for a project, with this
+// release's templates, into a separate directory. goscaf upgrade runs it for
+// the release a project was generated with and for the current one, so it
+// must keep working across releases.
+var RenderCmd = &cobra.Command{
+	Use:    "render",
+	Short:  "Render a project's generated files with this goscaf release",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		out, _ := cmd.Flags().GetString("out")
+		m := loadManifest(projectPath)
+
+		if err := renderProject(projectPath, m, out); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RenderCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	RenderCmd.Flags().String("out", "", "Directory to render into; must be empty")
+	RenderCmd.MarkFlagRequired("out")
+}
+
+// renderProject renders the files init and generate wrote for the project
+// into out, as they were before any later edits. Migrations and the OpenAPI
+// document are left out: the former must never change once applied and the
+// latter is rebuilt from the project on every generate.
+func renderProject(projectPath string, m *manifest.Manifest, out string) error {
+	projectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	// The init templates write to, and import, the module path relative to
+	// the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(out); err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	driver := m.SQLiteDriver
+	if driver == "" {
+		driver = "modernc"
+	}
+	rpc := strings.ToLower(m.RPC)
+	if rpc == "" {
+		rpc = "none"
+	}
+	scaffoldBackendFiles(m.Module, m.Framework, m.Database, m.ORM, rpc, effectiveSQLiteDriver(m.ORM, driver))
+	templates.InitTemplateCmd.Run(nil, []string{m.Module, m.Framework, m.ORM, rpc, m.Database})
+	root := m.Module
+
+	write := func(f resourceFile, formatted bool) error {
+		path := filepath.Join(root, f.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		content := f.content
+		if src, err := format.Source([]byte(content)); err == nil && formatted {
+			content = string(src)
+		}
+		return utils.CreateFile(path, content)
+	}
+	if err := write(resourceFile{"docs/docs.go", templates.DocsTemplate()}, true); err != nil {
+		return err
+	}
+
+	for _, res := range m.Resources {
+		r, err := loadResource(m, res)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %v\n", err)
+			continue
+		}
+		if r.Relations, err = relations(m, r, res.BelongsTo); err != nil {
+			return err
+		}
+		for _, f := range resourceFiles(r) {
+			if err := write(f, true); err != nil {
+				return err
+			}
+		}
+		for _, f := range sharedResourceFiles(r) {
+			if err := write(f, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if m.OpenAPI != "" {
+		spec := m.OpenAPI
+		if !filepath.IsAbs(spec) {
+			spec = filepath.Join(projectPath, spec)
+		}
+		doc, err := openapi.Load(spec)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", m.OpenAPI, err)
+		}
+		types, ops, err := doc.Resolve()
+		if err != nil {
+			return err
+		}
+		for _, f := range apiFiles(m, filepath.Base(spec), types, ops) {
+			if err := write(f, true); err != nil {
+				return err
+			}
+		}
+		json := filepath.Join(root, "internal/handlers/json.go")
+		if _, err := os.Stat(json); os.IsNotExist(err) && strings.ToLower(m.Framework) == "chi" {
+			if err := write(resourceFile{"internal/handlers/json.go", templates.ResourceJSONHelperTemplate()}, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	sql, _ := filepath.Glob(filepath.Join(root, "migrations", "*.sql"))
+	for _, path := range sql {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/samznd/goscaf/pkg/templates"
 	"github.com/samznd/goscaf/pkg/utils"
 
 	"github.com/spf13/cobra"
@@ -23,147 +24,359 @@ var ScaffoldBackendCmd = &cobra.Command{
 		}
 
 		projectPath, backend, database, orm := args[0], args[1], args[2], args[3]
-
-		// Create directories
-		directories := []string{
-			"cmd", "config", "internal", "internal/middleware",
-			"internal/models", "internal/repositories", "internal/services",
-			"internal/handlers", "internal/routes", "pkg/utils", "scripts",
+		rpc := "none"
+		if len(args) > 4 {
+			rpc = strings.ToLower(args[4])
 		}
-
-		for _, dir := range directories {
-			fullPath := filepath.Join(projectPath, dir)
-			if err := os.MkdirAll(fullPath, 0755); err != nil {
-				fmt.Printf("Error creating directory %s: %v\n", fullPath, err)
-			}
+		sqliteDriver := "modernc"
+		if len(args) > 5 {
+			sqliteDriver = strings.ToLower(args[5])
 		}
+		sqliteDriver = effectiveSQLiteDriver(orm, sqliteDriver)
 
-		// Generate files
-		mainContent := getMainFile(backend, projectPath)
-		databaseContent := getDatabaseFile(database, orm)
+		scaffoldBackendFiles(projectPath, backend, database, orm, rpc, sqliteDriver)
 
-		if databaseContent == "None" {
-			fmt.Printf("Error: Invalid database configuration. Database: %s, ORM: %s\n", database, orm)
-			os.Exit(1)
-		}
+		// Initialize go.mod and install dependencies
+		installDependencies(projectPath, backend, database, orm, rpc, sqliteDriver)
+	},
+}
 
-		envContent := `DB_USER=postgres
-DB_PASSWORD=postgres
-DB_HOST=localhost
-DB_PORT=5432
-DB_NAME=mydb`
-
-		utilsContent := getUtilsFile()
-		dockerfileContent := getDockerFile(projectPath)
-		dockerComposeContent := getDockerComposeFile(database)
-		// Create files
-		if err := utils.CreateFile(filepath.Join(projectPath, "cmd", "main.go"), mainContent); err != nil {
-			fmt.Printf("Error creating main.go: %v\n", err)
-		}
-		if err := utils.CreateFile(filepath.Join(projectPath, "config", "database.go"), databaseContent); err != nil {
-			fmt.Printf("Error creating database.go: %v\n", err)
-		}
-		if err := utils.CreateFile(filepath.Join(projectPath, ".env"), envContent); err != nil {
-			fmt.Printf("Error creating .env: %v\n", err)
+// scaffoldBackendFiles writes the directories and files of a new project,
+// everything init creates besides go.mod and the layer templates.
+func scaffoldBackendFiles(projectPath, backend, database, orm, rpc, sqliteDriver string) {
+	cgo := strings.ToLower(database) == "sqlite" && sqliteDriver == "mattn"
+	migrate := usesMigrations(database, orm)
+
+	// Create directories
+	directories := []string{
+		"cmd", "config", "internal", "internal/middleware",
+		"internal/models", "internal/repositories", "internal/services",
+		"internal/handlers", "internal/routes", "pkg/utils", "scripts",
+	}
+
+	for _, dir := range directories {
+		fullPath := filepath.Join(projectPath, dir)
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			fmt.Printf("Error creating directory %s: %v\n", fullPath, err)
 		}
-		if err := utils.CreateFile(filepath.Join(projectPath, "pkg/utils", "env_utils.go"), utilsContent); err != nil {
-			fmt.Printf("Error creating env_utils.go: %v\n", err)
+	}
+
+	// Generate files
+	mainContent := getMainFile(backend, projectPath, migrate, templates.RepositoryArg(strings.ToLower(orm), strings.ToLower(database)))
+	databaseContent := getDatabaseFile(database, orm, sqliteDriver, projectPath)
+
+	if databaseContent == "None" {
+		fmt.Printf("Error: Invalid database configuration. Database: %s, ORM: %s\n", database, orm)
+		os.Exit(1)
+	}
+
+	envContent := getEnvFile(database)
+
+	utilsContent := getUtilsFile()
+	dockerfileContent := getDockerFile(projectPath, cgo)
+	dockerComposeContent := getDockerComposeFile(database)
+	// Create files
+	if err := utils.CreateFile(filepath.Join(projectPath, "cmd", "main.go"), mainContent); err != nil {
+		fmt.Printf("Error creating main.go: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, "config", "database.go"), databaseContent); err != nil {
+		fmt.Printf("Error creating database.go: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, ".env"), envContent); err != nil {
+		fmt.Printf("Error creating .env: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, "pkg/utils", "env_utils.go"), utilsContent); err != nil {
+		fmt.Printf("Error creating env_utils.go: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, "Dockerfile"), dockerfileContent); err != nil {
+		fmt.Printf("Error creating Dockerfile: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, "docker-compose.yml"), dockerComposeContent); err != nil {
+		fmt.Printf("Error creating docker-compose.yml: %v\n", err)
+	}
+
+	if migrate {
+		scaffoldMigrations(projectPath, strings.ToLower(database), orm, sqliteDriver)
+	}
+	if rpc != "none" {
+		scaffoldRPC(projectPath, rpc, orm)
+	}
+	if strings.ToLower(orm) == "sqlc" {
+		scaffoldSqlc(projectPath, database)
+	}
+}
+
+// effectiveSQLiteDriver returns the SQLite driver a project ends up using.
+// Ent opens SQLite through the "sqlite3" driver name, which only mattn registers.
+func effectiveSQLiteDriver(orm, sqliteDriver string) string {
+	if strings.ToLower(orm) == "ent" {
+		return "mattn"
+	}
+	return sqliteDriver
+}
+
+// scaffoldMigrations writes the first numbered migration, the package that
+// embeds migrations/ and the runner main calls at startup.
+func scaffoldMigrations(projectPath, database, orm, sqliteDriver string) {
+	dir := filepath.Join(projectPath, "migrations")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Printf("Error creating directory %s: %v\n", dir, err)
+	}
+
+	up, down := getInitMigration(database)
+	files := []struct{ name, content string }{
+		{"migrations/000001_create_messages.up.sql", up},
+		{"migrations/000001_create_messages.down.sql", down},
+		{"migrations/migrations.go", getMigrationsEmbedFile()},
+		{"config/migrate.go", getMigrateFile(projectPath, database, orm, sqliteDriver)},
+	}
+	for _, f := range files {
+		if err := utils.CreateFile(filepath.Join(projectPath, f.name), f.content); err != nil {
+			fmt.Printf("Error creating %s: %v\n", f.name, err)
 		}
-		if err := utils.CreateFile(filepath.Join(projectPath, "Dockerfile"), dockerfileContent); err != nil {
-			fmt.Printf("Error creating Dockerfile: %v\n", err)
+	}
+}
+
+// scaffoldRPC writes the proto definition, buf configuration and the RPC
+// server that SetupRoutes mounts on the HTTP router.
+func scaffoldRPC(projectPath, rpc, orm string) {
+	protoDir := filepath.Join(projectPath, "proto", "message", "v1")
+	for _, dir := range []string{protoDir, filepath.Join(projectPath, "internal", "rpc")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Printf("Error creating directory %s: %v\n", dir, err)
 		}
-		if err := utils.CreateFile(filepath.Join(projectPath, "docker-compose.yml"), dockerComposeContent); err != nil {
-			fmt.Printf("Error creating docker-compose.yml: %v\n", err)
+	}
+
+	if err := utils.CreateFile(filepath.Join(protoDir, "message.proto"), templates.ProtoTemplate(projectPath, rpc)); err != nil {
+		fmt.Printf("Error creating message.proto: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, "buf.yaml"), templates.BufTemplate(rpc)); err != nil {
+		fmt.Printf("Error creating buf.yaml: %v\n", err)
+	}
+	if err := utils.CreateFile(filepath.Join(projectPath, "buf.gen.yaml"), templates.BufGenTemplate(rpc)); err != nil {
+		fmt.Printf("Error creating buf.gen.yaml: %v\n", err)
+	}
+	utils.CreateTemplate("rpc", "server.go", templates.RPCServerTemplate(projectPath, rpc), projectPath)
+}
+
+// scaffoldSqlc writes sqlc.yaml, the starter schema and queries, and the
+// go:generate hook that produces the internal/db package.
+func scaffoldSqlc(projectPath, database string) {
+	database = strings.ToLower(database)
+	for _, dir := range []string{"db/queries", "db/schema", "internal/db"} {
+		fullPath := filepath.Join(projectPath, dir)
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			fmt.Printf("Error creating directory %s: %v\n", fullPath, err)
 		}
+	}
 
-		// Initialize go.mod and install dependencies
-		installDependencies(projectPath, backend, database, orm)
-	},
+	files := []struct{ name, content string }{
+		{"sqlc.yaml", templates.SqlcConfigTemplate(database)},
+		{"db/schema/messages.sql", templates.SqlcSchemaTemplate(database)},
+		{"db/queries/messages.sql", templates.SqlcQueriesTemplate()},
+		{"internal/db/generate.go", templates.SqlcGenerateTemplate()},
+	}
+	for _, f := range files {
+		if err := utils.CreateFile(filepath.Join(projectPath, f.name), f.content); err != nil {
+			fmt.Printf("Error creating %s: %v\n", f.name, err)
+		}
+	}
 }
 
-func installDependencies(projectPath, backend string, database string, orm string) {
+func installDependencies(projectPath, backend string, database string, orm string, rpc string, sqliteDriver string) {
+	modules, err := dependencies(backend, database, orm, rpc, sqliteDriver)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	fmt.Println("📦 Initializing Go module...")
 	runCommand(projectPath, "go mod init "+projectPath)
 
 	fmt.Println("📦 Installing dependencies...")
+	for _, module := range modules {
+		runCommand(projectPath, "go get "+module)
+	}
+
+	tidy := "go mod tidy"
+	if rpc != "none" {
+		// The RPC server imports the stubs under gen/, which only exist once
+		// buf has run. Without buf, keep tidy from failing on them.
+		if _, err := exec.LookPath("buf"); err == nil {
+			runCommand(projectPath, "buf generate")
+		} else {
+			fmt.Println("⚠️  buf not found: run `buf generate` to create the RPC stubs in gen/")
+			tidy = "go mod tidy -e"
+		}
+	}
+
+	// Tidy up modules and ensure all dependencies are properly downloaded
+	runCommand(projectPath, tidy)
+	runCommand(projectPath, "go mod download")
+
+	if strings.ToLower(orm) == "sqlc" {
+		fmt.Println("ℹ️  Run `go generate ./...` to generate the sqlc queries in internal/db")
+	}
 
-	// Install common utilities
-	runCommand(projectPath, "go get github.com/joho/godotenv")
-	runCommand(projectPath, "go get golang.org/x/crypto")
+	fmt.Println("✅ Dependencies installed successfully!")
+}
+
+// dependencies returns the modules init adds with go get, in order, at
+// their pinned versions.
+func dependencies(backend, database, orm, rpc, sqliteDriver string) ([]string, error) {
+	stack, err := stackDependencies(backend, database, orm, sqliteDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	// Common utilities
+	modules := []string{"github.com/joho/godotenv", "golang.org/x/crypto", "github.com/mattn/go-isatty"}
+	modules = append(modules, stack...)
+
+	// RPC bridge, if selected
+	modules = append(modules, rpcModules(rpc)...)
+	return pinnedAll(modules), nil
+}
+
+// stackDependencies returns the modules the generated code needs for the
+// framework, database and ORM. goscaf check expects each in go.mod.
+func stackDependencies(backend, database, orm, sqliteDriver string) ([]string, error) {
+	modules, err := frameworkModules(backend)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := driverModules(database, sqliteDriver)
+	if err != nil {
+		return nil, err
+	}
+	modules = append(modules, driver...)
+	ormMods, err := ormModules(orm, database, sqliteDriver)
+	if err != nil {
+		return nil, err
+	}
+	modules = append(modules, ormMods...)
+
+	// Migration runner for SQL databases
+	if migratesWithGoose(database, orm, sqliteDriver) {
+		modules = append(modules, "github.com/pressly/goose/v3")
+	} else if usesMigrations(database, orm) {
+		modules = append(modules, "github.com/golang-migrate/migrate/v4")
+	}
+	return modules, nil
+}
 
-	// Fix missing dependencies
-	runCommand(projectPath, "go get github.com/mattn/go-isatty@v0.0.20")
+// rpcModules returns the modules of an RPC option, none for "none".
+func rpcModules(rpc string) []string {
+	switch rpc {
+	case "connect-rpc":
+		return []string{"connectrpc.com/connect", "google.golang.org/protobuf"}
+	case "grpc-gateway":
+		return []string{"github.com/grpc-ecosystem/grpc-gateway/v2", "github.com/improbable-eng/grpc-web",
+			"google.golang.org/grpc", "google.golang.org/protobuf", "google.golang.org/genproto/googleapis/api"}
+	}
+	return nil
+}
 
-	// Install backend framework
+// frameworkModules returns the modules of a web framework.
+func frameworkModules(backend string) ([]string, error) {
 	switch strings.ToLower(backend) {
 	case "fiber":
-		runCommand(projectPath, "go get github.com/gofiber/fiber/v3")
+		return []string{"github.com/gofiber/fiber/v3"}, nil
 	case "gin":
-		runCommand(projectPath, "go get github.com/gin-gonic/gin")
+		return []string{"github.com/gin-gonic/gin"}, nil
 	case "echo":
-		runCommand(projectPath, "go get github.com/labstack/echo/v4")
+		return []string{"github.com/labstack/echo/v4"}, nil
 	case "chi":
-		runCommand(projectPath, "go get github.com/go-chi/chi/v5")
+		return []string{"github.com/go-chi/chi/v5"}, nil
 	case "iris":
-		runCommand(projectPath, "go get github.com/kataras/iris/v12@latest")
-
-	default:
-		fmt.Printf("❌ Error: Invalid backend: %s\n", backend)
-		os.Exit(1)
+		return []string{"github.com/kataras/iris/v12"}, nil
 	}
+	return nil, fmt.Errorf("invalid backend: %s", backend)
+}
 
-	// Install database driver
+// driverModules returns the modules of a database's driver.
+func driverModules(database, sqliteDriver string) ([]string, error) {
 	switch strings.ToLower(database) {
-	case "postgres":
-		runCommand(projectPath, "go get github.com/lib/pq")
+	case "postgres", "cockroachdb":
+		return []string{"github.com/lib/pq"}, nil
 	case "mysql":
-		runCommand(projectPath, "go get github.com/go-sql-driver/mysql")
+		return []string{"github.com/go-sql-driver/mysql"}, nil
 	case "sqlite":
-		runCommand(projectPath, "go get github.com/mattn/go-sqlite3")
-
-	default:
-		fmt.Printf("❌ Error: Invalid database: %s\n", database)
-		os.Exit(1)
+		if sqliteDriver == "modernc" {
+			return []string{"modernc.org/sqlite"}, nil
+		}
+		return []string{"github.com/mattn/go-sqlite3"}, nil
+	case "sqlserver":
+		return []string{"github.com/microsoft/go-mssqldb"}, nil
+	case "mongodb":
+		return []string{"go.mongodb.org/mongo-driver/v2"}, nil
 	}
+	return nil, fmt.Errorf("invalid database: %s", database)
+}
 
-	// Install ORM if selected
+// ormModules returns the modules of an ORM and of its driver or dialect
+// for the database, if any.
+func ormModules(orm, database, sqliteDriver string) ([]string, error) {
+	var modules []string
 	switch strings.ToLower(orm) {
 	case "gorm":
-		runCommand(projectPath, "go get gorm.io/gorm")
-		// Install GORM database drivers
+		modules = append(modules, "gorm.io/gorm")
 		switch strings.ToLower(database) {
-		case "postgres":
-			runCommand(projectPath, "go get gorm.io/driver/postgres")
+		case "postgres", "cockroachdb":
+			modules = append(modules, "gorm.io/driver/postgres")
 		case "mysql":
-			runCommand(projectPath, "go get gorm.io/driver/mysql")
+			modules = append(modules, "gorm.io/driver/mysql")
 		case "sqlite":
-			runCommand(projectPath, "go get gorm.io/driver/sqlite")
-
+			if sqliteDriver == "modernc" {
+				modules = append(modules, "github.com/glebarez/sqlite")
+			} else {
+				modules = append(modules, "gorm.io/driver/sqlite")
+			}
+		case "sqlserver":
+			modules = append(modules, "gorm.io/driver/sqlserver")
 		default:
-			fmt.Printf("❌ Error: Invalid database: %s\n", database)
-			os.Exit(1)
+			return nil, fmt.Errorf("invalid database: %s", database)
 		}
 	case "xorm":
-		runCommand(projectPath, "go get xorm.io/xorm")
+		modules = append(modules, "xorm.io/xorm")
 	case "ent":
-		runCommand(projectPath, "go get entgo.io/ent")
-		runCommand(projectPath, "go get entgo.io/ent/cmd/ent")
+		modules = append(modules, "entgo.io/ent", "entgo.io/ent/cmd/ent")
+	case "bun":
+		modules = append(modules, "github.com/uptrace/bun")
+		switch strings.ToLower(database) {
+		case "postgres", "cockroachdb":
+			modules = append(modules, "github.com/uptrace/bun/dialect/pgdialect", "github.com/jackc/pgx/v5")
+		case "mysql":
+			modules = append(modules, "github.com/uptrace/bun/dialect/mysqldialect")
+		case "sqlite":
+			modules = append(modules, "github.com/uptrace/bun/dialect/sqlitedialect")
+		case "sqlserver":
+			modules = append(modules, "github.com/uptrace/bun/dialect/mssqldialect")
+		}
+	case "sqlx/pgx":
+		switch strings.ToLower(database) {
+		case "postgres", "cockroachdb":
+			modules = append(modules, "github.com/jackc/pgx/v5")
+		default:
+			modules = append(modules, "github.com/jmoiron/sqlx")
+		}
 	}
-
-	// Tidy up modules and ensure all dependencies are properly downloaded
-	runCommand(projectPath, "go mod tidy")
-	runCommand(projectPath, "go mod download")
-
-	fmt.Println("✅ Dependencies installed successfully!")
+	return modules, nil
 }
 
-func runCommand(dir, command string) {
+// runCommand runs a shell command in dir, with env added to the
+// environment, and exits if it fails.
+func runCommand(dir, command string, env ...string) {
 	cmd := exec.Command("sh", "-c", command)
 	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		fmt.Printf("❌ Error executing command '%s': %v\n", command, err)
+		fmt.Println("ℹ️  Run `goscaf doctor` to diagnose your environment")
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,232 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/internal/merge"
+	"github.com/samznd/goscaf/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var UpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Re-apply this release's templates to a project, keeping local changes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, _ := cmd.Flags().GetString("dir")
+		from, _ := cmd.Flags().GetString("from")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		reject, _ := cmd.Flags().GetBool("reject")
+		m := loadManifest(projectPath)
+
+		if err := upgradeProject(projectPath, m, from, dryRun, reject); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	UpgradeCmd.Flags().String("dir", ".", "Project root containing "+manifest.FileName)
+	UpgradeCmd.Flags().String("from", "", "goscaf binary of the release the project was generated with (default: go run it at the recorded version)")
+	UpgradeCmd.Flags().Bool("dry-run", false, "Report what would change without writing anything")
+	UpgradeCmd.Flags().Bool("reject", false, "Keep your side of each conflict and write goscaf's to <file>.rej instead of adding conflict markers")
+}
+
+// upgradeProject three-way merges every generated file: the base is the
+// file rendered by the release recorded in the manifest, theirs is the file
+// rendered by this release and ours is the file on disk.
+func upgradeProject(projectPath string, m *manifest.Manifest, from string, dryRun, reject bool) error {
+	if m.Pack != nil {
+		return fmt.Errorf("the project was created from pack %s; upgrade only re-applies goscaf's own templates", m.Pack.Name)
+	}
+	if canonicalVersion(m.Version) == Version && from == "" {
+		fmt.Printf("✅ Already generated by goscaf %s\n", Version)
+		return nil
+	}
+	base := []string{from}
+	if from == "" {
+		if m.Version == "" || m.Version == "dev" {
+			return fmt.Errorf("the project was generated by a development build of goscaf; pass --from with that build")
+		}
+		base = []string{"go", "run", "github.com/samznd/goscaf@" + canonicalVersion(m.Version)}
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "goscaf-upgrade-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	baseFiles, err := renderWith(base, projectPath, m, filepath.Join(tmp, "base"))
+	if err != nil {
+		return fmt.Errorf("rendering the templates of goscaf %s: %w", m.Version, err)
+	}
+	theirFiles, err := renderWith([]string{self}, projectPath, m, filepath.Join(tmp, "theirs"))
+	if err != nil {
+		return fmt.Errorf("rendering the templates of goscaf %s: %w", Version, err)
+	}
+
+	paths := map[string]bool{}
+	for rel := range baseFiles {
+		paths[rel] = true
+	}
+	for rel := range theirFiles {
+		paths[rel] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for rel := range paths {
+		sorted = append(sorted, rel)
+	}
+	sort.Strings(sorted)
+
+	var updated, merged, added, conflicted int
+	write := func(rel, content string) error {
+		if dryRun {
+			return nil
+		}
+		return os.WriteFile(filepath.Join(projectPath, rel), []byte(content), 0644)
+	}
+	for _, rel := range sorted {
+		baseContent, inBase := baseFiles[rel]
+		theirs, inTheirs := theirFiles[rel]
+		data, err := os.ReadFile(filepath.Join(projectPath, rel))
+		exists := err == nil
+		ours := string(data)
+
+		switch {
+		case !inTheirs:
+			if exists {
+				fmt.Printf("ℹ️  %s is no longer generated by goscaf; delete it if nothing uses it\n", rel)
+			}
+			continue
+		case !exists && inBase:
+			fmt.Printf("ℹ️  %s was deleted; skipped\n", rel)
+			continue
+		case !exists:
+			added++
+			if dryRun {
+				fmt.Printf("✅ Would create %s\n", rel)
+				continue
+			}
+			path := filepath.Join(projectPath, rel)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := utils.CreateFile(path, theirs); err != nil {
+				return err
+			}
+			if err := m.Track(projectPath, path); err != nil {
+				return err
+			}
+			continue
+		case inBase && baseContent == theirs, ours == theirs:
+			continue
+		}
+
+		// A file goscaf did not write before has no base, so every
+		// difference from it is a conflict.
+		unmodified := inBase && !m.Modified(projectPath, rel)
+		result := merge.Merge(baseContent, ours, theirs)
+		text := result.Markers("yours", "goscaf "+Version)
+		if n := result.Conflicts(); n > 0 {
+			conflicted++
+			if reject {
+				text, rej := result.Reject()
+				if err := write(rel, text); err != nil {
+					return err
+				}
+				if err := write(rel+".rej", rej); err != nil {
+					return err
+				}
+				fmt.Printf("⚠️  %s: %d change(s) conflict with yours; see %s.rej\n", rel, n, rel)
+			} else {
+				if err := write(rel, text); err != nil {
+					return err
+				}
+				fmt.Printf("⚠️  %s: %d change(s) conflict with yours; resolve the <<<<<<< markers\n", rel, n)
+			}
+			continue
+		}
+
+		if text == ours {
+			continue
+		}
+		if err := write(rel, text); err != nil {
+			return err
+		}
+		if unmodified {
+			updated++
+			fmt.Printf("✅ Updated %s\n", rel)
+			if !dryRun {
+				if err := m.Track(projectPath, filepath.Join(projectPath, rel)); err != nil {
+					return err
+				}
+			}
+		} else {
+			merged++
+			fmt.Printf("🔀 Merged %s with your changes\n", rel)
+		}
+	}
+
+	summary := fmt.Sprintf("%d updated, %d merged, %d added, %d with conflicts", updated, merged, added, conflicted)
+	if dryRun {
+		fmt.Printf("ℹ️  Dry run, nothing was written: %s\n", summary)
+		return nil
+	}
+	previous := m.Version
+	m.Version = Version
+	writeDocs(projectPath, m)
+	if err := m.Save(projectPath); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Upgraded from goscaf %s to %s: %s\n", previous, Version, summary)
+	if updated+merged+added > 0 {
+		fmt.Println("ℹ️  Run `go mod tidy` to add any new dependencies")
+	}
+	return nil
+}
+
+// renderWith runs the render command of a goscaf binary for the project and
+// returns the rendered files by path relative to the project root.
+func renderWith(goscaf []string, projectPath string, m *manifest.Manifest, out string) (map[string]string, error) {
+	abs, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	args := append(goscaf[1:len(goscaf):len(goscaf)], "render", "--dir", abs, "--out", out)
+	cmd := exec.Command(goscaf[0], args...)
+	var stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = io.Discard, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s: %v\n%s", goscaf[0], strings.Join(args, " "), err, stderr.String())
+	}
+
+	root := filepath.Join(out, m.Module)
+	files := map[string]string{}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	return files, err
+}
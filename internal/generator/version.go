@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"runtime/debug"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Version is the goscaf release, set at build time with
+// -ldflags "-X github.com/samznd/goscaf/internal/generator.Version=...".
+// Without the flag it is the release go install recorded, or "dev" for a
+// build from a checkout, whose pseudo-version go run may not find.
+// Generated projects record it in their manifest.
+var Version = "dev"
+
+func init() {
+	if Version == "dev" {
+		if info, ok := debug.ReadBuildInfo(); ok && semver.IsValid(info.Main.Version) &&
+			semver.Build(info.Main.Version) == "" && !module.IsPseudoVersion(info.Main.Version) {
+			Version = info.Main.Version
+		}
+	}
+	Version = canonicalVersion(Version)
+}
+
+// canonicalVersion adds the leading v of a Go module version to a release
+// number such as 1.2.0, so it can be passed to go run and go install.
+func canonicalVersion(v string) string {
+	if v != "" && v[0] >= '0' && v[0] <= '9' {
+		return "v" + v
+	}
+	return v
+}
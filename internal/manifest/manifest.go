@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/regions"
+)
+
+// FileName is the manifest goscaf writes at the root of every generated project.
+const FileName = ".goscaf.json"
+
+// Manifest records how a project was generated: the goscaf version, the
+// choices made during init and a hash of every file goscaf wrote.
+type Manifest struct {
+	Version      string            `json:"version"`
+	Module       string            `json:"module"`
+	Framework    string            `json:"framework"`
+	Database     string            `json:"database"`
+	ORM          string            `json:"orm"`
+	RPC          string            `json:"rpc,omitempty"`
+	SQLiteDriver string            `json:"sqlite_driver,omitempty"`
+	OpenAPI      string            `json:"openapi,omitempty"`
+	Pack         *Pack             `json:"pack,omitempty"`
+	Resources    []Resource        `json:"resources,omitempty"`
+	Files        map[string]string `json:"files"`
+}
+
+// Pack records the template pack a project was created from and the
+// answers given to its prompts. The lockfile next to the manifest pins the
+// pack's exact content.
+type Pack struct {
+	Name    string         `json:"name"`
+	Version string         `json:"version,omitempty"`
+	Source  string         `json:"source"`
+	Ref     string         `json:"ref,omitempty"`
+	Answers map[string]any `json:"answers,omitempty"`
+}
+
+// Resource records a resource added by goscaf generate and the files written
+// for it, so later commands can find or remove them. Fields holds the field
+// specs the resource was generated from, so it can be rendered again.
+// HasMany lists children declared with --has-many, including those not
+// generated yet; a child generated later belongs to this resource.
+type Resource struct {
+	Name       string   `json:"name"`
+	Table      string   `json:"table"`
+	PrimaryKey string   `json:"primary_key,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+	BelongsTo  []string `json:"belongs_to,omitempty"`
+	HasMany    []string `json:"has_many,omitempty"`
+	Files      []string `json:"files"`
+}
+
+// Load reads the manifest from the project root.
+func Load(projectPath string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	return &m, nil
+}
+
+// Save writes the manifest to the project root.
+func (m *Manifest) Save(projectPath string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(projectPath, FileName), append(data, '\n'), 0644)
+}
+
+// Track records the current hash of each file. Paths may be absolute or
+// relative to the working directory; they are stored relative to the
+// project root with forward slashes.
+func (m *Manifest) Track(projectPath string, paths ...string) error {
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	for _, path := range paths {
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		sum, err := Hash(path)
+		if err != nil {
+			return err
+		}
+		m.Files[filepath.ToSlash(rel)] = sum
+	}
+	return nil
+}
+
+// FindResource returns the index of the named resource, or -1.
+func (m *Manifest) FindResource(name string) int {
+	for i, r := range m.Resources {
+		if strings.EqualFold(r.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Modified reports whether a tracked file differs from the hash recorded
+// when goscaf last wrote it. Untracked and missing files count as modified.
+func (m *Manifest) Modified(projectPath, rel string) bool {
+	want, ok := m.Files[rel]
+	if !ok {
+		return true
+	}
+	got, err := Hash(filepath.Join(projectPath, filepath.FromSlash(rel)))
+	return err != nil || got != want
+}
+
+// Paths returns the tracked file paths in sorted order.
+func (m *Manifest) Paths() []string {
+	paths := make([]string, 0, len(m.Files))
+	for path := range m.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Hash returns the hex-encoded SHA-256 of a file's contents. The contents
+// of custom regions are left out, so edits inside them do not count as
+// modifications.
+func Hash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(regions.Strip(data))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,194 @@
+// Package merge implements a line-based three-way merge, in the manner of
+// diff3: changes made on only one side of a common base are combined, and
+// regions both sides changed differently are reported as conflicts.
+package merge
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Result is the outcome of a merge: a sequence of resolved regions and
+// conflicts, in order.
+type Result struct {
+	chunks []chunk
+}
+
+type chunk struct {
+	lines    []string // the resolved lines when conflict is false
+	conflict bool
+	base     []string
+	ours     []string
+	theirs   []string
+	baseLine int // 1-based line of the region in base
+}
+
+// Merge merges the changes from base to ours with those from base to theirs.
+func Merge(base, ours, theirs string) *Result {
+	b, o, t := splitLines(base), splitLines(ours), splitLines(theirs)
+	mo, mt := matches(b, o), matches(b, t)
+
+	r := &Result{}
+	i, j, k := 0, 0, 0
+	for {
+		// The next base line kept by both sides ends the unstable region.
+		n := i
+		for n < len(b) && (mo[n] < 0 || mt[n] < 0) {
+			n++
+		}
+		oe, te := len(o), len(t)
+		if n < len(b) {
+			oe, te = mo[n], mt[n]
+		}
+		r.resolve(b[i:n], o[j:oe], t[k:te], i+1)
+		if n == len(b) {
+			break
+		}
+		r.add(chunk{lines: b[n : n+1]})
+		i, j, k = n+1, oe+1, te+1
+	}
+	return r
+}
+
+// resolve adds an unstable region, taking whichever side changed it.
+func (r *Result) resolve(base, ours, theirs []string, line int) {
+	switch {
+	case len(base) == 0 && len(ours) == 0 && len(theirs) == 0:
+	case slices.Equal(ours, base):
+		r.add(chunk{lines: theirs})
+	case slices.Equal(theirs, base), slices.Equal(ours, theirs):
+		r.add(chunk{lines: ours})
+	default:
+		r.add(chunk{conflict: true, base: base, ours: ours, theirs: theirs, baseLine: line})
+	}
+}
+
+func (r *Result) add(c chunk) {
+	// Merge adjacent resolved regions to keep the chunk list short.
+	if n := len(r.chunks); n > 0 && !c.conflict && !r.chunks[n-1].conflict {
+		last := &r.chunks[n-1]
+		last.lines = append(slices.Clip(last.lines), c.lines...)
+		return
+	}
+	r.chunks = append(r.chunks, c)
+}
+
+// Conflicts returns the number of conflicting regions.
+func (r *Result) Conflicts() int {
+	n := 0
+	for _, c := range r.chunks {
+		if c.conflict {
+			n++
+		}
+	}
+	return n
+}
+
+// Markers returns the merged text with each conflict written between
+// <<<<<<<, ======= and >>>>>>> markers labelled ours and theirs.
+func (r *Result) Markers(ours, theirs string) string {
+	var b strings.Builder
+	for _, c := range r.chunks {
+		if !c.conflict {
+			writeLines(&b, c.lines)
+			continue
+		}
+		b.WriteString("<<<<<<< " + ours + "\n")
+		writeLines(&b, c.ours)
+		b.WriteString("=======\n")
+		writeLines(&b, c.theirs)
+		b.WriteString(">>>>>>> " + theirs + "\n")
+	}
+	return b.String()
+}
+
+// Reject returns the merged text with ours kept in every conflict, and the
+// rejected changes from base to theirs as unified diff hunks.
+func (r *Result) Reject() (text, rej string) {
+	var t, rj strings.Builder
+	line := 1
+	for _, c := range r.chunks {
+		if !c.conflict {
+			writeLines(&t, c.lines)
+			line += len(c.lines)
+			continue
+		}
+		fmt.Fprintf(&rj, "@@ -%d,%d +%d,%d @@\n", c.baseLine, len(c.base), line, len(c.theirs))
+		for _, l := range c.base {
+			writeLines(&rj, []string{"-" + l})
+		}
+		for _, l := range c.theirs {
+			writeLines(&rj, []string{"+" + l})
+		}
+		writeLines(&t, c.ours)
+		line += len(c.ours)
+	}
+	return t.String(), rj.String()
+}
+
+// writeLines writes lines, ending the last one with a newline if it lacks one.
+func writeLines(b *strings.Builder, lines []string) {
+	for _, l := range lines {
+		b.WriteString(l)
+		if !strings.HasSuffix(l, "\n") {
+			b.WriteByte('\n')
+		}
+	}
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matches returns, for each line of a, the index of the line of b it is
+// paired with in a longest common subsequence, or -1.
+func matches(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+
+	// The common prefix and suffix pair up without a table.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		m[pre] = pre
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		m[len(a)-1-suf] = len(b) - 1 - suf
+		suf++
+	}
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:].
+	w := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*w)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else {
+				lcs[i*w+j] = max(lcs[(i+1)*w+j], lcs[i*w+j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			m[pre+i] = pre + j
+			i++
+			j++
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return m
+}
@@ -0,0 +1,137 @@
+package merge
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          int
+	}{
+		{
+			name: "unchanged",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "only ours changed",
+			base: "a\nb\nc\n", ours: "a\nB\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "only theirs changed",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nB\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "only theirs added lines",
+			base: "a\nc\n", ours: "a\nc\n", theirs: "a\nb\nc\nd\n",
+			want: "a\nb\nc\nd\n",
+		},
+		{
+			name: "only ours deleted lines",
+			base: "a\nb\nc\n", ours: "a\n", theirs: "a\nb\nc\n",
+			want: "a\n",
+		},
+		{
+			name: "both changed different regions",
+			base: "a\nb\nc\nd\ne\n", ours: "A\nb\nc\nd\ne\n", theirs: "a\nb\nc\nd\nE\n",
+			want: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name: "both made the same change",
+			base: "a\nb\nc\n", ours: "a\nB\nc\nd\n", theirs: "a\nB\nc\nd\n",
+			want: "a\nB\nc\nd\n",
+		},
+		{
+			name: "both changed the same line",
+			base: "a\nb\nc\n", ours: "a\nours\nc\n", theirs: "a\ntheirs\nc\n",
+			want:      "a\n<<<<<<< yours\nours\n=======\ntheirs\n>>>>>>> new\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "both changed adjacent lines",
+			base: "a\nb\nc\n", ours: "a\nB\nc\n", theirs: "a\nb\nC\n",
+			want:      "a\n<<<<<<< yours\nB\nc\n=======\nb\nC\n>>>>>>> new\n",
+			conflicts: 1,
+		},
+		{
+			name: "ours deleted what theirs changed",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nB\nc\n",
+			want:      "a\n<<<<<<< yours\n=======\nB\n>>>>>>> new\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "both added to an empty base",
+			base: "", ours: "x\n", theirs: "y\n",
+			want:      "<<<<<<< yours\nx\n=======\ny\n>>>>>>> new\n",
+			conflicts: 1,
+		},
+		{
+			name: "two conflicts",
+			base: "a\nb\nc\nd\ne\n", ours: "A1\nb\nc\nd\nE1\n", theirs: "A2\nb\nc\nd\nE2\n",
+			want:      "<<<<<<< yours\nA1\n=======\nA2\n>>>>>>> new\nb\nc\nd\n<<<<<<< yours\nE1\n=======\nE2\n>>>>>>> new\n",
+			conflicts: 2,
+		},
+		{
+			name: "missing final newline",
+			base: "a\nb", ours: "a\nb", theirs: "a\nB",
+			want: "a\nB\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Merge(tt.base, tt.ours, tt.theirs)
+			if got := r.Conflicts(); got != tt.conflicts {
+				t.Errorf("Conflicts() = %d, want %d", got, tt.conflicts)
+			}
+			if got := r.Markers("yours", "new"); got != tt.want {
+				t.Errorf("Markers() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		text, rej          string
+	}{
+		{
+			name: "no conflict",
+			base: "a\nb\nc\n", ours: "A\nb\nc\n", theirs: "a\nb\nC\n",
+			text: "A\nb\nC\n",
+		},
+		{
+			name: "changed line",
+			base: "a\nb\nc\n", ours: "a\nours\nc\n", theirs: "a\ntheirs\nc\n",
+			text: "a\nours\nc\n",
+			rej:  "@@ -2,1 +2,1 @@\n-b\n+theirs\n",
+		},
+		{
+			name: "hunk position follows the merged text",
+			base: "a\nb\nc\nd\n", ours: "x\ny\na\nb\nc\nours\n", theirs: "a\nb\nc\ntheirs1\ntheirs2\n",
+			text: "x\ny\na\nb\nc\nours\n",
+			rej:  "@@ -4,1 +6,2 @@\n-d\n+theirs1\n+theirs2\n",
+		},
+		{
+			name: "ours deleted what theirs changed",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nB\nc\n",
+			text: "a\nc\n",
+			rej:  "@@ -2,1 +2,1 @@\n-b\n+B\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, rej := Merge(tt.base, tt.ours, tt.theirs).Reject()
+			if text != tt.text {
+				t.Errorf("text =\n%s\nwant\n%s", text, tt.text)
+			}
+			if rej != tt.rej {
+				t.Errorf("rej =\n%s\nwant\n%s", rej, tt.rej)
+			}
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+	"github.com/samznd/goscaf/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Build returns the OpenAPI 3.1 document of a generated project: the
+// starter message route, the CRUD routes of every resource in the manifest
+// with schemas read from its model, and the operations of the spec the
+// project was generated from, if any.
+func Build(projectPath string, m *manifest.Manifest) ([]byte, error) {
+	server := "/api"
+	if strings.EqualFold(m.Framework, "chi") {
+		server = "/api/v1"
+	}
+
+	paths := map[string]any{
+		"/message": map[string]any{
+			"get": map[string]any{
+				"operationId": "getMessage",
+				"summary":     "Get the starter message",
+				"responses": map[string]any{
+					"200": jsonResponse("OK", map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"message": map[string]any{"type": "string"}},
+						"required":   []string{"message"},
+					}),
+				},
+			},
+		},
+	}
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"error": map[string]any{"type": "string"}},
+			"required":   []string{"error"},
+		},
+	}
+
+	mongo := strings.EqualFold(m.Database, "mongodb")
+	ids := map[string]map[string]any{}
+	for _, r := range m.Resources {
+		path := filepath.Join(projectPath, "internal", "models", utils.ToSnake(r.Name)+".go")
+		model, err := modelSchema(path, r.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		schemas[r.Name] = model
+
+		idSchema := map[string]any{"type": "string"}
+		if !mongo {
+			if s, ok := propertyFor(path, r.Name, r.PrimaryKey); ok {
+				idSchema = s
+			}
+		}
+		ids[r.Name] = idSchema
+	}
+	for _, r := range m.Resources {
+		addResourcePaths(paths, m, r, ids)
+	}
+
+	components := map[string]any{"schemas": schemas}
+	if spec := m.OpenAPI; spec != "" {
+		if !filepath.IsAbs(spec) {
+			spec = filepath.Join(projectPath, spec)
+		}
+		if err := mergeSpec(spec, paths, components); err != nil {
+			return nil, fmt.Errorf("%s: %w", m.OpenAPI, err)
+		}
+	}
+
+	doc := map[string]any{
+		"openapi":    "3.1.0",
+		"info":       map[string]any{"title": m.Module, "version": "1.0.0"},
+		"servers":    []any{map[string]any{"url": server}},
+		"paths":      paths,
+		"components": components,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// addResourcePaths adds the operations registered by a resource's routes,
+// including the nested list route under each resource it belongs to. ids
+// holds the id schema of every resource.
+func addResourcePaths(paths map[string]any, m *manifest.Manifest, r manifest.Resource, ids map[string]map[string]any) {
+	collection := "/" + utils.ToKebab(r.Table)
+	plural := utils.ToPascal(utils.Pluralize(utils.ToSnake(r.Name)))
+	ref := map[string]any{"$ref": "#/components/schemas/" + r.Name}
+	body := map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": ref}},
+	}
+	id := []any{map[string]any{"name": "id", "in": "path", "required": true, "schema": ids[r.Name]}}
+	badRequest := jsonResponse("Invalid request", errorRef())
+	notFound := jsonResponse("Not found", errorRef())
+
+	getParams := id
+	if include := includes(m, r); len(include) > 0 {
+		getParams = append([]any{}, id...)
+		getParams = append(getParams, map[string]any{
+			"name": "include", "in": "query", "description": "Load a related resource",
+			"schema": map[string]any{"type": "string", "enum": include},
+		})
+	}
+	for _, name := range r.BelongsTo {
+		i := m.FindResource(name)
+		if i < 0 {
+			continue
+		}
+		parent := m.Resources[i]
+		paths["/"+utils.ToKebab(parent.Table)+"/{id}"+collection] = map[string]any{
+			"get": map[string]any{
+				"operationId": "list" + plural + "By" + parent.Name,
+				"tags":        []string{r.Name},
+				"parameters":  []any{map[string]any{"name": "id", "in": "path", "required": true, "schema": ids[parent.Name]}},
+				"responses": map[string]any{
+					"200": jsonResponse("OK", map[string]any{"type": "array", "items": ref}),
+					"400": badRequest,
+				},
+			},
+		}
+	}
+
+	paths[collection] = map[string]any{
+		"get": map[string]any{
+			"operationId": "list" + plural,
+			"tags":        []string{r.Name},
+			"responses": map[string]any{
+				"200": jsonResponse("OK", map[string]any{"type": "array", "items": ref}),
+			},
+		},
+		"post": map[string]any{
+			"operationId": "create" + r.Name,
+			"tags":        []string{r.Name},
+			"requestBody": body,
+			"responses": map[string]any{
+				"201": jsonResponse("Created", ref),
+				"400": badRequest,
+			},
+		},
+	}
+	paths[collection+"/{id}"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "get" + r.Name,
+			"tags":        []string{r.Name},
+			"parameters":  getParams,
+			"responses": map[string]any{
+				"200": jsonResponse("OK", ref),
+				"400": badRequest,
+				"404": notFound,
+			},
+		},
+		"put": map[string]any{
+			"operationId": "update" + r.Name,
+			"tags":        []string{r.Name},
+			"parameters":  id,
+			"requestBody": body,
+			"responses": map[string]any{
+				"200": jsonResponse("OK", ref),
+				"400": badRequest,
+				"404": notFound,
+			},
+		},
+		"delete": map[string]any{
+			"operationId": "delete" + r.Name,
+			"tags":        []string{r.Name},
+			"parameters":  id,
+			"responses": map[string]any{
+				"204": map[string]any{"description": "Deleted"},
+				"400": badRequest,
+				"404": notFound,
+			},
+		},
+	}
+}
+
+// includes returns the values the include query parameter of a resource
+// accepts: its parents, then the plural of each resource that belongs to it.
+func includes(m *manifest.Manifest, r manifest.Resource) []string {
+	var out []string
+	for _, name := range r.BelongsTo {
+		out = append(out, utils.ToSnake(name))
+	}
+	for _, child := range m.Resources {
+		if slices.Contains(child.BelongsTo, r.Name) {
+			out = append(out, utils.Pluralize(utils.ToSnake(child.Name)))
+		}
+	}
+	return out
+}
+
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+func errorRef() map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/Error"}
+}
+
+// modelStruct parses a model file and returns the named struct.
+func modelStruct(path, name string) (*ast.StructType, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok && ts.Name.Name == name {
+				return st, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("type %s not found in %s", name, filepath.Base(path))
+}
+
+// modelSchema describes a model struct by its JSON encoding. Fields tagged
+// omitempty are optional; pointer fields are nullable.
+func modelSchema(path, name string) (map[string]any, error) {
+	st, err := modelStruct(path, name)
+	if err != nil {
+		return nil, err
+	}
+	props := map[string]any{}
+	var required []string
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 || !f.Names[0].IsExported() {
+			continue
+		}
+		key, omitempty, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+		props[key] = fieldSchema(f.Type)
+		applyRules(props[key].(map[string]any), f)
+		if !omitempty {
+			required = append(required, key)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// propertyFor returns the schema of a model field given its Go name.
+func propertyFor(path, name, field string) (map[string]any, bool) {
+	st, err := modelStruct(path, name)
+	if err != nil || field == "" {
+		return nil, false
+	}
+	for _, f := range st.Fields.List {
+		if len(f.Names) > 0 && f.Names[0].Name == field {
+			return fieldSchema(f.Type), true
+		}
+	}
+	return nil, false
+}
+
+// jsonName returns the JSON key of a struct field and whether it is
+// omitempty. ok is false for fields excluded from JSON.
+func jsonName(f *ast.Field) (name string, omitempty, ok bool) {
+	name = f.Names[0].Name
+	if f.Tag == nil {
+		return name, false, true
+	}
+	tag, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return name, false, true
+	}
+	value, found := reflect.StructTag(tag).Lookup("json")
+	if !found {
+		return name, false, true
+	}
+	if value == "-" {
+		return "", false, false
+	}
+	key, opts, _ := strings.Cut(value, ",")
+	if key != "" {
+		name = key
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), true
+}
+
+// applyRules adds the constraints of a field's validate tag to its schema.
+func applyRules(schema map[string]any, f *ast.Field) {
+	if f.Tag == nil {
+		return
+	}
+	tag, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return
+	}
+	rules, ok := reflect.StructTag(tag).Lookup("validate")
+	if !ok {
+		return
+	}
+	isString := schema["type"] == "string" || fmt.Sprint(schema["type"]) == "[string null]"
+	for _, rule := range strings.Split(rules, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		n, numErr := strconv.ParseFloat(value, 64)
+		switch {
+		case name == "email":
+			schema["format"] = "email"
+		case name == "url" || name == "uri":
+			schema["format"] = "uri"
+		case name == "uuid" || name == "uuid4":
+			schema["format"] = "uuid"
+		case name == "oneof":
+			schema["enum"] = strings.Fields(value)
+		case numErr != nil:
+		case isString && (name == "max" || name == "lte"):
+			schema["maxLength"] = n
+		case isString && (name == "min" || name == "gte"):
+			schema["minLength"] = n
+		case isString && name == "len":
+			schema["minLength"], schema["maxLength"] = n, n
+		case name == "max" || name == "lte":
+			schema["maximum"] = n
+		case name == "min" || name == "gte":
+			schema["minimum"] = n
+		case name == "lt":
+			schema["exclusiveMaximum"] = n
+		case name == "gt":
+			schema["exclusiveMinimum"] = n
+		}
+	}
+}
+
+// fieldSchema maps a Go type expression to a JSON schema.
+func fieldSchema(expr ast.Expr) map[string]any {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		s := fieldSchema(t.X)
+		if typ, ok := s["type"].(string); ok {
+			s["type"] = []string{typ, "null"}
+		}
+		return s
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": fieldSchema(t.Elt)}
+	case *ast.MapType:
+		return map[string]any{"type": "object", "additionalProperties": fieldSchema(t.Value)}
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok {
+			return map[string]any{}
+		}
+		switch pkg.Name + "." + t.Sel.Name {
+		case "time.Time":
+			return map[string]any{"type": "string", "format": "date-time"}
+		case "uuid.UUID":
+			return map[string]any{"type": "string", "format": "uuid"}
+		case "decimal.Decimal":
+			return map[string]any{"type": "string", "format": "decimal"}
+		case "bson.ObjectID", "primitive.ObjectID":
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{}
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return map[string]any{"type": "string"}
+		case "bool":
+			return map[string]any{"type": "boolean"}
+		case "int64", "uint64":
+			return map[string]any{"type": "integer", "format": "int64"}
+		case "int32", "uint32":
+			return map[string]any{"type": "integer", "format": "int32"}
+		case "int", "int8", "int16", "uint", "uint8", "uint16":
+			return map[string]any{"type": "integer"}
+		case "float32":
+			return map[string]any{"type": "number", "format": "float"}
+		case "float64":
+			return map[string]any{"type": "number", "format": "double"}
+		}
+		if t.IsExported() {
+			return map[string]any{"$ref": "#/components/schemas/" + t.Name}
+		}
+	}
+	return map[string]any{}
+}
+
+// mergeSpec copies the paths and components of an OpenAPI document into the
+// built document.
+func mergeSpec(path string, paths, components map[string]any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var spec map[string]any
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	for k, v := range asMap(spec["paths"]) {
+		paths[k] = v
+	}
+	for kind, entries := range asMap(spec["components"]) {
+		merged, ok := components[kind].(map[string]any)
+		if !ok {
+			merged = map[string]any{}
+			components[kind] = merged
+		}
+		for k, v := range asMap(entries) {
+			merged[k] = v
+		}
+	}
+	return nil
+}
+
+func asMap(v any) map[string]any {
+	m, _ := normalize(v).(map[string]any)
+	return m
+}
+
+// normalize converts the map[any]any values YAML may produce for non-string
+// keys, such as response codes, into map[string]any so they encode as JSON.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = normalize(e)
+		}
+		return t
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[fmt.Sprint(k)] = normalize(e)
+		}
+		return out
+	case []any:
+		for i, e := range t {
+			t[i] = normalize(e)
+		}
+		return t
+	}
+	return v
+}
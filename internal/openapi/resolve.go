@@ -0,0 +1,324 @@
+package openapi
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samznd/goscaf/pkg/templates"
+	"github.com/samznd/goscaf/pkg/utils"
+)
+
+// methodOrder is the order operations of the same path are generated in.
+var methodOrder = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// reservedVars are names used inside generated handlers and services that a
+// parameter variable must not shadow.
+var reservedVars = map[string]bool{
+	"ctx": true, "body": true, "res": true, "err": true, "raw": true, "v": true,
+	"h": true, "s": true, "c": true, "r": true, "w": true,
+}
+
+type resolver struct {
+	doc   *Document
+	types []templates.APIType
+	index map[string]int
+}
+
+// Resolve turns the document into the DTO types and operations goscaf
+// generates code for. Operations are ordered by path, then method.
+func (d *Document) Resolve() ([]templates.APIType, []templates.APIOperation, error) {
+	r := &resolver{doc: d, index: map[string]int{}}
+
+	names := make([]string, 0, len(d.Components.Schemas))
+	for name := range d.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.declare(utils.ToPascal(name), d.Components.Schemas[name])
+	}
+
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []templates.APIOperation
+	seen := map[string]string{}
+	for _, path := range paths {
+		item := d.Paths[path]
+		byMethod := item.Operations()
+		for _, method := range methodOrder {
+			op, ok := byMethod[method]
+			if !ok {
+				continue
+			}
+			resolved, err := r.operation(path, method, item, op)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if prev, dup := seen[resolved.Name]; dup {
+				return nil, nil, fmt.Errorf("%s %s: operation name %s is already used by %s", method, path, resolved.Name, prev)
+			}
+			seen[resolved.Name] = method + " " + path
+			ops = append(ops, resolved)
+		}
+	}
+	return r.types, ops, nil
+}
+
+func (r *resolver) operation(path, method string, item PathItem, op *Operation) (templates.APIOperation, error) {
+	name := utils.ToPascal(op.OperationID)
+	if name == "" {
+		name = operationName(method, path)
+	}
+	out := templates.APIOperation{Name: name, Method: method, Path: path, Summary: op.Summary, Status: 200}
+
+	params, err := r.parameters(path, item.Parameters, op.Parameters)
+	if err != nil {
+		return out, err
+	}
+	for _, p := range params {
+		v := utils.ToCamel(p.Name)
+		if reservedVars[v] || token.Lookup(v).IsKeyword() {
+			v += "Param"
+		}
+		out.Params = append(out.Params, templates.APIParam{
+			Name: p.Name, Var: v, In: p.In, Required: p.Required || p.In == "path",
+			Type: r.goType(p.Schema, name+utils.ToPascal(p.Name)),
+		})
+	}
+
+	if body := op.RequestBody; body != nil {
+		if body.Ref != "" {
+			if body = r.doc.Components.RequestBodies[refName(body.Ref)]; body == nil {
+				return out, fmt.Errorf("unknown request body %s", op.RequestBody.Ref)
+			}
+		}
+		if s := jsonSchema(body.Content); s != nil {
+			out.Body = r.goType(s, name+"Request")
+		}
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) > 0 {
+		if status, err := strconv.Atoi(codes[0]); err == nil {
+			out.Status = status
+		}
+		res := op.Responses[codes[0]]
+		if res != nil && res.Ref != "" {
+			if res = r.doc.Components.Responses[refName(res.Ref)]; res == nil {
+				return out, fmt.Errorf("unknown response %s", op.Responses[codes[0]].Ref)
+			}
+		}
+		if res != nil {
+			if s := jsonSchema(res.Content); s != nil {
+				out.Response = r.goType(s, name+"Response")
+			}
+		}
+	}
+	return out, nil
+}
+
+// parameters merges path-level and operation-level parameters. Path
+// parameters come first, in the order they appear in the path.
+func (r *resolver) parameters(path string, lists ...[]*Parameter) ([]*Parameter, error) {
+	byKey := map[string]*Parameter{}
+	var order []string
+	for _, list := range lists {
+		for _, p := range list {
+			if p.Ref != "" {
+				ref := r.doc.Components.Parameters[refName(p.Ref)]
+				if ref == nil {
+					return nil, fmt.Errorf("unknown parameter %s", p.Ref)
+				}
+				p = ref
+			}
+			if p.In != "path" && p.In != "query" {
+				continue
+			}
+			key := p.In + ":" + p.Name
+			if _, ok := byKey[key]; !ok {
+				order = append(order, key)
+			}
+			byKey[key] = p
+		}
+	}
+
+	var params []*Parameter
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := seg[1 : len(seg)-1]
+			p, ok := byKey["path:"+name]
+			if !ok {
+				p = &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: SchemaType{"string"}}}
+			}
+			params = append(params, p)
+		}
+	}
+	for _, key := range order {
+		if p := byKey[key]; p.In == "query" {
+			params = append(params, p)
+		}
+	}
+	return params, nil
+}
+
+// declare adds a named component schema: objects become structs and
+// everything else a defined type.
+func (r *resolver) declare(name string, s *Schema) {
+	if isObject(s) && len(s.Properties) > 0 || len(s.AllOf) > 1 {
+		r.addStruct(name, s)
+		return
+	}
+	r.index[name] = len(r.types)
+	r.types = append(r.types, templates.APIType{Name: name, Doc: docLine(s.Description)})
+	r.types[r.index[name]].Alias = r.goType(s, name+"Item")
+}
+
+func (r *resolver) addStruct(name string, s *Schema) {
+	if _, ok := r.index[name]; ok {
+		return
+	}
+	r.index[name] = len(r.types)
+	r.types = append(r.types, templates.APIType{Name: name, Doc: docLine(s.Description)})
+
+	props, required := r.properties(s)
+	var fields []templates.APIField
+	for _, p := range props {
+		typ := r.goType(p.Schema, name+utils.ToPascal(p.Name))
+		if nullable(p.Schema) && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "any" {
+			typ = "*" + typ
+		}
+		fields = append(fields, templates.APIField{
+			Name: utils.ToPascal(p.Name), JSON: p.Name, Type: typ, Required: required[p.Name],
+		})
+	}
+	r.types[r.index[name]].Fields = fields
+}
+
+// properties returns the properties of an object, flattening allOf.
+func (r *resolver) properties(s *Schema) (Properties, map[string]bool) {
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	props := append(Properties{}, s.Properties...)
+	for _, part := range s.AllOf {
+		if part.Ref != "" {
+			if part = r.doc.Components.Schemas[refName(part.Ref)]; part == nil {
+				continue
+			}
+		}
+		p, req := r.properties(part)
+		props = append(props, p...)
+		for name := range req {
+			required[name] = true
+		}
+	}
+	return props, required
+}
+
+// goType returns the Go type for a schema, declaring inline objects as
+// structs called name.
+func (r *resolver) goType(s *Schema, name string) string {
+	switch {
+	case s == nil:
+		return "any"
+	case s.Ref != "":
+		return utils.ToPascal(refName(s.Ref))
+	case len(s.AllOf) == 1:
+		return r.goType(s.AllOf[0], name)
+	case len(s.AllOf) > 1:
+		r.addStruct(name, s)
+		return name
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		return "any"
+	case s.Type.Is("array"):
+		return "[]" + r.goType(s.Items, name+"Item")
+	case isObject(s):
+		if len(s.Properties) > 0 {
+			r.addStruct(name, s)
+			return name
+		}
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			return "map[string]" + r.goType(s.AdditionalProperties.Schema, name+"Value")
+		}
+		return "map[string]any"
+	case s.Type.Is("string"):
+		if s.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case s.Type.Is("integer"):
+		switch s.Format {
+		case "int64":
+			return "int64"
+		case "int32":
+			return "int32"
+		}
+		return "int"
+	case s.Type.Is("number"):
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case s.Type.Is("boolean"):
+		return "bool"
+	}
+	return "any"
+}
+
+func isObject(s *Schema) bool {
+	return s.Type.Is("object") || len(s.Type) == 0 && len(s.Properties) > 0
+}
+
+func nullable(s *Schema) bool {
+	return s != nil && (s.Nullable || s.Type.Is("null"))
+}
+
+// jsonSchema returns the schema of the JSON media type, if any.
+func jsonSchema(content map[string]MediaType) *Schema {
+	if mt, ok := content["application/json"]; ok {
+		return mt.Schema
+	}
+	for typ, mt := range content {
+		if strings.Contains(typ, "json") {
+			return mt.Schema
+		}
+	}
+	return nil
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func docLine(description string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	return line
+}
+
+// operationName derives a name for operations without an operationId,
+// e.g. GET /pets/{petId} becomes GetPetsByPetID.
+func operationName(method, path string) string {
+	name := utils.ToPascal(strings.ToLower(method))
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name += "By" + utils.ToPascal(seg[1:len(seg)-1])
+		} else {
+			name += utils.ToPascal(seg)
+		}
+	}
+	return name
+}
@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the subset of an OpenAPI 3.0/3.1 document goscaf generates
+// code from. JSON documents are read by the same YAML decoder.
+type Document struct {
+	OpenAPI    string              `yaml:"openapi"`
+	Info       Info                `yaml:"info"`
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components Components          `yaml:"components"`
+}
+
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type Components struct {
+	Schemas       map[string]*Schema      `yaml:"schemas"`
+	Parameters    map[string]*Parameter   `yaml:"parameters"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	Responses     map[string]*Response    `yaml:"responses"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Patch      *Operation   `yaml:"patch"`
+}
+
+// Operations returns the operations of the path item keyed by HTTP method.
+func (p PathItem) Operations() map[string]*Operation {
+	ops := map[string]*Operation{}
+	for method, op := range map[string]*Operation{"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete, "PATCH": p.Patch} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Ref      string               `yaml:"$ref"`
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Ref         string               `yaml:"$ref"`
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Schema struct {
+	Ref                  string      `yaml:"$ref"`
+	Type                 SchemaType  `yaml:"type"`
+	Format               string      `yaml:"format"`
+	Description          string      `yaml:"description"`
+	Nullable             bool        `yaml:"nullable"`
+	Items                *Schema     `yaml:"items"`
+	Properties           Properties  `yaml:"properties"`
+	Required             []string    `yaml:"required"`
+	AdditionalProperties *Additional `yaml:"additionalProperties"`
+	AllOf                []*Schema   `yaml:"allOf"`
+	OneOf                []*Schema   `yaml:"oneOf"`
+	AnyOf                []*Schema   `yaml:"anyOf"`
+}
+
+// Additional holds "additionalProperties", which is either a boolean or a
+// schema for the map values.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		a.Allowed = node.Value == "true"
+		return nil
+	}
+	a.Allowed, a.Schema = true, &Schema{}
+	return node.Decode(a.Schema)
+}
+
+// SchemaType holds "type", which OpenAPI 3.1 allows to be a list such as
+// ["string", "null"].
+type SchemaType []string
+
+func (t *SchemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = SchemaType{node.Value}
+		return nil
+	}
+	var types []string
+	if err := node.Decode(&types); err != nil {
+		return err
+	}
+	*t = types
+	return nil
+}
+
+// Is reports whether the schema type includes name.
+func (t SchemaType) Is(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Property is a named object property.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps object properties in the order the document declares them.
+type Properties []Property
+
+func (p *Properties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var s Schema
+		if err := node.Content[i+1].Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: node.Content[i].Value, Schema: &s})
+	}
+	return nil
+}
+
+// Load reads an OpenAPI document from a YAML or JSON file.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.OpenAPI) < 2 || doc.OpenAPI[:2] != "3." {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q (expected 3.x)", doc.OpenAPI)
+	}
+	return &doc, nil
+}
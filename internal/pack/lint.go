@@ -0,0 +1,339 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// MaxCombinations bounds the answer combinations Lint renders.
+const MaxCombinations = 256
+
+// Issue is something Lint found wrong with a pack. File is the file of the
+// pack it is about. Warnings do not make the pack unusable.
+type Issue struct {
+	File    string
+	Message string
+	Warning bool
+}
+
+func (i Issue) String() string {
+	return i.File + ": " + i.Message
+}
+
+// Lint checks the templates of a loaded pack: that every one parses and
+// only uses defined variables, and that every combination of select and
+// confirm answers renders, writes every path at most once, leaves no path
+// that alternative files provide uncovered, and produces Go that parses.
+// Go that is not gofmt-clean is a warning.
+func (p *Pack) Lint() []Issue {
+	var issues []Issue
+	report := func(file string, warning bool, format string, args ...any) {
+		issue := Issue{File: file, Message: fmt.Sprintf(format, args...), Warning: warning}
+		if !slices.Contains(issues, issue) {
+			issues = append(issues, issue)
+		}
+	}
+	// A problem of a file that shows in several combinations is only
+	// reported for the first.
+	reported := map[string]bool{}
+	reportOnce := func(key, file string, warning bool, format string, args ...any) {
+		if !reported[file+" "+key] {
+			reported[file+" "+key] = true
+			report(file, warning, format, args...)
+		}
+	}
+
+	defined := map[string]bool{}
+	for _, name := range Builtins {
+		defined[name] = true
+	}
+	for _, pr := range p.Prompts {
+		defined[pr.Name] = true
+	}
+	checkTemplate := func(file, what, text string) {
+		t, err := template.New(file).Parse(text)
+		if err != nil {
+			report(file, false, "%s does not parse: %v", what, err)
+			return
+		}
+		if t.Tree == nil {
+			return
+		}
+		for _, name := range undefinedVars(t.Tree.Root, defined) {
+			report(file, false, "%s uses undefined variable .%s", what, name)
+		}
+	}
+	for _, f := range p.Files {
+		if f.When != "" {
+			checkTemplate(FileName, "when of "+f.Src, f.When)
+		}
+		if f.Dest != "" {
+			checkTemplate(FileName, "dest of "+f.Src, f.Dest)
+		}
+		root := filepath.Join(p.dir, filepath.FromSlash(f.Src))
+		err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(file, ".tmpl") {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(p.dir, file)
+			checkTemplate(filepath.ToSlash(rel), "template", string(data))
+			return nil
+		})
+		if err != nil {
+			report(f.Src, false, "%v", err)
+		}
+	}
+	if len(issues) > 0 {
+		return issues // rendering would only repeat them
+	}
+
+	combinations, total := p.Combinations(MaxCombinations)
+	if total > len(combinations) {
+		report(FileName, true, "only the first %d of %d answer combinations were checked", len(combinations), total)
+	}
+	// Paths that conditional files render to, by the files, and the paths
+	// every combination renders.
+	conditional := map[string]map[string]bool{}
+	rendered := make([]map[string]bool, len(combinations))
+	selected := map[string]bool{}
+	for i, answers := range combinations {
+		when := p.Describe(answers)
+		outputs, err := p.Render(p.Vars("example", "dev", answers))
+		if err != nil {
+			reportOnce("render", FileName, false, "rendering fails when %s: %v", when, err)
+			continue
+		}
+		rendered[i] = map[string]bool{}
+		from := map[string]string{}
+		for _, out := range outputs {
+			selected[out.Src] = true
+			if src, ok := from[out.Path]; ok {
+				reportOnce("conflict "+out.Path, out.Src, false, "renders to %s, as %s does, when %s", out.Path, src, when)
+			}
+			from[out.Path] = out.Src
+			rendered[i][out.Path] = true
+			if p.conditional(out.Src) {
+				if conditional[out.Path] == nil {
+					conditional[out.Path] = map[string]bool{}
+				}
+				conditional[out.Path][out.Src] = true
+			}
+			if strings.HasSuffix(out.Path, ".go") {
+				checkGo(out, when, reportOnce)
+			}
+		}
+	}
+
+	for _, f := range p.Files {
+		if f.When == "" {
+			continue
+		}
+		root := filepath.Join(p.dir, filepath.FromSlash(f.Src))
+		filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(p.dir, file)
+			if rel = filepath.ToSlash(rel); !selected[rel] {
+				report(rel, true, "no answer combination writes it")
+			}
+			return nil
+		})
+	}
+	paths := make([]string, 0, len(conditional))
+	for path, srcs := range conditional {
+		if len(srcs) > 1 {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for i, answers := range combinations {
+			if rendered[i] != nil && !rendered[i][path] {
+				report(FileName, false, "none of the files that render to %s is written when %s", path, p.Describe(answers))
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// conditional reports whether the pack file src is written only when a
+// condition holds.
+func (p *Pack) conditional(src string) bool {
+	for _, f := range p.Files {
+		if src == f.Src || strings.HasPrefix(src, strings.TrimSuffix(f.Src, "/")+"/") {
+			if f.When != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func checkGo(out Output, when string, report func(string, string, bool, string, ...any)) {
+	src := []byte(out.Content)
+	if _, err := parser.ParseFile(token.NewFileSet(), out.Path, src, parser.AllErrors); err != nil {
+		report("parse", out.Src, false, "renders Go that does not parse when %s: %v", when, err)
+		return
+	}
+	formatted, err := format.Source(src)
+	if err == nil && !bytes.Equal(formatted, src) {
+		// init formats what it writes, so this only costs readers of the
+		// template.
+		report("gofmt", out.Src, true, "renders Go that is not gofmt-clean when %s", when)
+	}
+}
+
+// undefinedVars returns the fields of the root data that the template uses
+// and that are not defined. Fields inside range and with, where dot is
+// something else, are not checked.
+func undefinedVars(root parse.Node, defined map[string]bool) []string {
+	var names []string
+	var walk func(node parse.Node, atRoot bool)
+	walk = func(node parse.Node, atRoot bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, atRoot)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, atRoot)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, atRoot)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, atRoot)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, atRoot)
+		case *parse.FieldNode:
+			if atRoot && !defined[n.Ident[0]] && !slices.Contains(names, n.Ident[0]) {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.VariableNode:
+			// $ is always the root data.
+			if n.Ident[0] == "$" && len(n.Ident) > 1 && !defined[n.Ident[1]] && !slices.Contains(names, n.Ident[1]) {
+				names = append(names, n.Ident[1])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, atRoot)
+			walk(n.ElseList, atRoot)
+		case *parse.RangeNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, false)
+			walk(n.ElseList, atRoot)
+		case *parse.WithNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, false)
+			walk(n.ElseList, atRoot)
+		case *parse.TemplateNode:
+			walk(n.Pipe, atRoot)
+		}
+	}
+	walk(root, true)
+	return names
+}
+
+// Combinations returns up to limit combinations of answers: every option
+// of each select prompt and both answers of each confirm prompt, with input
+// prompts at their defaults. It also returns how many combinations there
+// are in all.
+func (p *Pack) Combinations(limit int) ([]map[string]any, int) {
+	axes := map[string][]any{}
+	for _, pr := range p.Prompts {
+		switch pr.Type {
+		case Select:
+			for _, option := range pr.Options {
+				axes[pr.Name] = append(axes[pr.Name], option)
+			}
+		case Confirm:
+			axes[pr.Name] = []any{false, true}
+		}
+	}
+	return p.product(axes, limit)
+}
+
+// Samples returns the combinations of answers the test matrix declares, or
+// only the defaults if it declares none.
+func (p *Pack) Samples() []map[string]any {
+	axes := map[string][]any{}
+	for name, values := range p.Test.Matrix {
+		pr, _ := p.prompt(name)
+		for _, value := range values {
+			answer, _ := pr.Answer(value)
+			axes[name] = append(axes[name], answer)
+		}
+	}
+	samples, _ := p.product(axes, -1)
+	return samples
+}
+
+// product returns up to limit (all if negative) combinations of the values
+// of the axes, varying later prompts fastest; prompts without an axis get
+// their default answer. It also returns the number of combinations.
+func (p *Pack) product(axes map[string][]any, limit int) ([]map[string]any, int) {
+	total := 1
+	for _, values := range axes {
+		total *= len(values)
+	}
+	n := total
+	if limit >= 0 && n > limit {
+		n = limit
+	}
+	combinations := make([]map[string]any, n)
+	for i := range combinations {
+		answers := map[string]any{}
+		rest := i
+		for j := len(p.Prompts) - 1; j >= 0; j-- {
+			pr := p.Prompts[j]
+			values, ok := axes[pr.Name]
+			if !ok || len(values) == 0 {
+				answers[pr.Name] = pr.DefaultAnswer()
+				continue
+			}
+			answers[pr.Name] = values[rest%len(values)]
+			rest /= len(values)
+		}
+		combinations[i] = answers
+	}
+	return combinations, total
+}
+
+// Describe formats answers as name=value pairs in prompt order.
+func (p *Pack) Describe(answers map[string]any) string {
+	var pairs []string
+	for _, pr := range p.Prompts {
+		if pr.Type == Select || pr.Type == Confirm || p.Test.Matrix[pr.Name] != nil {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", pr.Name, answers[pr.Name]))
+		}
+	}
+	if len(pairs) == 0 {
+		return "the defaults are used"
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,307 @@
+// Package pack loads and renders template packs: directories, usually Git
+// repositories, holding a pack.yaml and the templates of a whole project.
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the pack definition at the root of every pack.
+const FileName = "pack.yaml"
+
+// Pack is a parsed pack.yaml.
+type Pack struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description,omitempty"`
+	Prompts     []Prompt `yaml:"prompts,omitempty"`
+	Files       []File   `yaml:"files"`
+	Hooks       Hooks    `yaml:"hooks,omitempty"`
+	Test        Test     `yaml:"test,omitempty"`
+
+	dir string
+}
+
+// Prompt types.
+const (
+	Input   = "input"
+	Select  = "select"
+	Confirm = "confirm"
+)
+
+// Prompt is a question asked during init. Its answer is available to
+// templates as .<Name>: a string, or a bool for confirm prompts. A select
+// prompt only accepts one of its Options, and is not asked when it has only
+// one.
+type Prompt struct {
+	Name    string   `yaml:"name"`
+	Message string   `yaml:"message,omitempty"`
+	Type    string   `yaml:"type,omitempty"`
+	Options []string `yaml:"options,omitempty"`
+	Default string   `yaml:"default,omitempty"`
+}
+
+// File maps a file or directory of the pack to a path in the project. Src
+// files ending in .tmpl are rendered with text/template and lose the
+// suffix; others are copied as they are. Dest defaults to Src and may use
+// template actions. When, if set, is a template that must render to "true"
+// for the file to be written.
+type File struct {
+	Src  string `yaml:"src"`
+	Dest string `yaml:"dest,omitempty"`
+	When string `yaml:"when,omitempty"`
+}
+
+// Hooks are shell commands run in the project directory at points of init:
+// PreRender before any file is written, Post once the files are written and
+// PostInstall once the dependencies are installed. They see the project's
+// metadata in GOSCAF_* environment variables.
+type Hooks struct {
+	PreRender   []string `yaml:"pre_render,omitempty"`
+	Post        []string `yaml:"post,omitempty"`
+	PostInstall []string `yaml:"post_install,omitempty"`
+}
+
+// Test declares the samples goscaf pack test renders: every combination
+// of the answers in Matrix, keyed by prompt name, with the other prompts at
+// their defaults.
+type Test struct {
+	Matrix map[string][]string `yaml:"matrix,omitempty"`
+}
+
+// Load reads and validates the pack in dir.
+func Load(dir string) (*Pack, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, err
+	}
+	var p Pack
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName, err)
+	}
+	p.dir = dir
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName, err)
+	}
+	return &p, nil
+}
+
+// Dir returns the directory the pack was loaded from.
+func (p *Pack) Dir() string { return p.dir }
+
+func (p *Pack) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	seen := map[string]bool{}
+	for _, name := range Builtins {
+		seen[name] = true
+	}
+	for _, pr := range p.Prompts {
+		switch {
+		case pr.Name == "":
+			return fmt.Errorf("a prompt has no name")
+		case seen[pr.Name]:
+			return fmt.Errorf("prompt %s is declared twice or shadows a built-in variable", pr.Name)
+		}
+		seen[pr.Name] = true
+		switch pr.Type {
+		case "", Input:
+		case Select:
+			if len(pr.Options) == 0 {
+				return fmt.Errorf("select prompt %s has no options", pr.Name)
+			}
+			if pr.Default != "" && !slices.Contains(pr.Options, pr.Default) {
+				return fmt.Errorf("prompt %s: default %q is not one of its options", pr.Name, pr.Default)
+			}
+		case Confirm:
+			if _, err := strconv.ParseBool(pr.Default); pr.Default != "" && err != nil {
+				return fmt.Errorf("prompt %s: default %q is not a bool", pr.Name, pr.Default)
+			}
+		default:
+			return fmt.Errorf("prompt %s has unknown type %q (expected input, select or confirm)", pr.Name, pr.Type)
+		}
+	}
+	for name, values := range p.Test.Matrix {
+		pr, ok := p.prompt(name)
+		if !ok {
+			return fmt.Errorf("test matrix: no prompt %s", name)
+		}
+		for _, value := range values {
+			if _, err := pr.Answer(value); err != nil {
+				return fmt.Errorf("test matrix: %w", err)
+			}
+		}
+	}
+	for _, f := range p.Files {
+		if f.Src == "" {
+			return fmt.Errorf("a file has no src")
+		}
+		if !local(f.Src) {
+			return fmt.Errorf("file %s is outside the pack", f.Src)
+		}
+		if _, err := os.Stat(filepath.Join(p.dir, filepath.FromSlash(f.Src))); err != nil {
+			return fmt.Errorf("file %s: %w", f.Src, err)
+		}
+	}
+	return nil
+}
+
+func (p *Pack) prompt(name string) (Prompt, bool) {
+	for _, pr := range p.Prompts {
+		if pr.Name == name {
+			return pr, true
+		}
+	}
+	return Prompt{}, false
+}
+
+// local reports whether a slash-separated path stays inside its root.
+func local(p string) bool {
+	return filepath.IsLocal(filepath.FromSlash(p))
+}
+
+// Builtins are the variables every template can use besides the answers:
+// the project name, which is also its module path, the pack and the goscaf
+// release.
+var Builtins = []string{"ProjectName", "Module", "PackName", "PackVersion", "GoscafVersion"}
+
+// Vars returns the template variables for the answers.
+func (p *Pack) Vars(projectName, goscafVersion string, answers map[string]any) map[string]any {
+	vars := map[string]any{
+		"ProjectName":   projectName,
+		"Module":        projectName,
+		"PackName":      p.Name,
+		"PackVersion":   p.Version,
+		"GoscafVersion": goscafVersion,
+	}
+	for k, v := range answers {
+		vars[k] = v
+	}
+	return vars
+}
+
+// DefaultAnswer returns the answer a prompt gets when it is not asked: its
+// default, the first option of a select prompt, false for a confirm prompt
+// and the empty string for an input prompt.
+func (pr Prompt) DefaultAnswer() any {
+	if pr.Default != "" {
+		answer, _ := pr.Answer(pr.Default)
+		return answer
+	}
+	switch pr.Type {
+	case Select:
+		return pr.Options[0]
+	case Confirm:
+		return false
+	}
+	return ""
+}
+
+// Answer converts a value given for a prompt, e.g. with --set, checking it
+// is allowed.
+func (pr Prompt) Answer(value string) (any, error) {
+	switch pr.Type {
+	case Confirm:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, not %q", pr.Name, value)
+		}
+		return b, nil
+	case Select:
+		if !slices.Contains(pr.Options, value) {
+			return nil, fmt.Errorf("%s must be one of %s, not %q", pr.Name, strings.Join(pr.Options, ", "), value)
+		}
+	}
+	return value, nil
+}
+
+// Output is a rendered file, with a slash-separated path relative to the
+// project root. Src is the file of the pack it comes from.
+type Output struct {
+	Path    string
+	Content string
+	Src     string
+}
+
+// Render renders the files whose condition holds for vars.
+func (p *Pack) Render(vars map[string]any) ([]Output, error) {
+	var out []Output
+	for _, f := range p.Files {
+		if f.When != "" {
+			ok, err := expand("when of "+f.Src, f.When, vars)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(ok) != "true" {
+				continue
+			}
+		}
+		dest := f.Dest
+		if dest == "" {
+			dest = f.Src
+		}
+		dest, err := expand("dest of "+f.Src, dest, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		root := filepath.Join(p.dir, filepath.FromSlash(f.Src))
+		err = filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(root, file)
+			target := path.Join(dest, filepath.ToSlash(rel))
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			content := string(data)
+			name, _ := filepath.Rel(p.dir, file)
+			name = filepath.ToSlash(name)
+			if t, ok := strings.CutSuffix(target, ".tmpl"); ok {
+				if content, err = expand(name, content, vars); err != nil {
+					return err
+				}
+				target = t
+			}
+			if !local(target) {
+				return fmt.Errorf("%s renders to %s, outside the project", f.Src, target)
+			}
+			out = append(out, Output{Path: path.Clean(target), Content: content, Src: name})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// expand executes text as a template. Referring to a variable that is not
+// defined is an error.
+func expand(name, text string, vars map[string]any) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, vars); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,206 @@
+package pack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LockFileName is the lockfile written next to the manifest of a project
+// created from a pack.
+const LockFileName = ".goscaf.lock"
+
+// Lock pins the pack a project was created from, so the same pack can be
+// fetched again: Commit is the Git commit the ref resolved to and Sum a
+// hash of the pack's files.
+type Lock struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Source  string `json:"source"`
+	Ref     string `json:"ref,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Sum     string `json:"sum"`
+}
+
+// Fetched is a pack ready to render. Close removes the checkout of a Git
+// pack.
+type Fetched struct {
+	*Pack
+	Lock Lock
+	tmp  string
+}
+
+func (f *Fetched) Close() error {
+	if f.tmp == "" {
+		return nil
+	}
+	return os.RemoveAll(f.tmp)
+}
+
+// Fetch loads the pack at source: a directory, or git+<url>[@<ref>] for a
+// Git repository, e.g. git+file:///srv/packs/acme.git@v1.2. The ref may be
+// a tag, branch or commit and defaults to the repository's HEAD.
+func Fetch(source string) (*Fetched, error) {
+	url, ok := strings.CutPrefix(source, "git+")
+	if !ok {
+		dir, err := filepath.Abs(source)
+		if err != nil {
+			return nil, err
+		}
+		return load(dir, Lock{Source: dir}, "")
+	}
+
+	ref := ""
+	if at := strings.LastIndex(url, "@"); at > strings.LastIndex(url, "/") {
+		url, ref = url[:at], url[at+1:]
+	}
+	return FetchGit(url, ref)
+}
+
+// FetchGit clones the repository at url and checks out ref, or HEAD if ref
+// is empty.
+func FetchGit(url, ref string) (*Fetched, error) {
+	tmp, err := os.MkdirTemp("", "goscaf-pack-")
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*Fetched, error) {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	dir := filepath.Join(tmp, "pack")
+	if _, err := git("", "clone", "--quiet", url, dir); err != nil {
+		return fail(err)
+	}
+	rev := "HEAD"
+	if ref != "" {
+		rev = ref
+	}
+	commit, err := git(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return fail(fmt.Errorf("no ref %s in %s", rev, url))
+	}
+	if _, err := git(dir, "checkout", "--quiet", "--detach", commit); err != nil {
+		return fail(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+		return fail(err)
+	}
+	f, err := load(dir, Lock{Source: "git+" + url, Ref: ref, Commit: commit}, tmp)
+	if err != nil {
+		return fail(err)
+	}
+	return f, nil
+}
+
+// FetchLocked fetches the pack a lockfile pins, by commit for a Git pack,
+// and checks it still has the same files.
+func FetchLocked(lock Lock) (*Fetched, error) {
+	var f *Fetched
+	var err error
+	if url, ok := strings.CutPrefix(lock.Source, "git+"); ok && lock.Commit != "" {
+		f, err = FetchGit(url, lock.Commit)
+	} else {
+		f, err = Fetch(lock.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.Lock.Sum != lock.Sum {
+		f.Close()
+		return nil, fmt.Errorf("pack %s at %s has changed since it was locked (sum %s, locked %s)", lock.Name, lock.Source, f.Lock.Sum, lock.Sum)
+	}
+	f.Lock.Ref = lock.Ref
+	return f, nil
+}
+
+func load(dir string, lock Lock, tmp string) (*Fetched, error) {
+	p, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := Sum(dir)
+	if err != nil {
+		return nil, err
+	}
+	lock.Name, lock.Version, lock.Sum = p.Name, p.Version, sum
+	return &Fetched{Pack: p, Lock: lock, tmp: tmp}, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Sum returns a hash of the paths and contents of the files in dir,
+// ignoring a .git directory.
+func Sum(dir string) (string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(h, "%s %d\n", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadLock reads a lockfile.
+func ReadLock(path string) (Lock, error) {
+	var lock Lock
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return lock, err
+	}
+	var file struct {
+		Pack Lock `json:"pack"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return lock, fmt.Errorf("%s: %w", path, err)
+	}
+	return file.Pack, nil
+}
+
+// WriteLock writes the lockfile of the project at projectPath.
+func WriteLock(projectPath string, lock Lock) error {
+	data, err := json.MarshalIndent(struct {
+		Pack Lock `json:"pack"`
+	}{lock}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(projectPath, LockFileName), append(data, '\n'), 0644)
+}
@@ -0,0 +1,125 @@
+package pack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// run runs git in dir, failing the test if it fails.
+func run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := git(dir, append([]string{"-c", "user.name=goscaf", "-c", "user.email=goscaf@example.com"}, args...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// commitPack writes a pack of the given version to work and commits it.
+func commitPack(t *testing.T, work, version string) string {
+	t.Helper()
+	yaml := "name: acme\nversion: " + version + "\nfiles:\n  - src: README.md.tmpl\n"
+	if err := os.WriteFile(filepath.Join(work, FileName), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "# {{ .ProjectName }} from {{ .PackName }} {{ .PackVersion }}\n"
+	if err := os.WriteFile(filepath.Join(work, "README.md.tmpl"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(t, work, "add", "-A")
+	run(t, work, "commit", "--quiet", "-m", "acme "+version)
+	return run(t, work, "rev-parse", "HEAD")
+}
+
+func TestFetchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// A bare repository with v1.0.0 tagged and v1.1.0 on main.
+	bare := filepath.Join(t.TempDir(), "acme.git")
+	run(t, "", "init", "--quiet", "--bare", bare)
+	run(t, bare, "symbolic-ref", "HEAD", "refs/heads/main")
+	work := t.TempDir()
+	run(t, work, "init", "--quiet")
+	v1 := commitPack(t, work, "1.0.0")
+	run(t, work, "tag", "v1.0.0")
+	v2 := commitPack(t, work, "1.1.0")
+	run(t, work, "push", "--quiet", "--tags", bare, "HEAD:refs/heads/main")
+
+	url := "file://" + bare
+	tests := []struct {
+		name    string
+		source  string
+		version string
+		ref     string
+		commit  string
+	}{
+		{"head", "git+" + url, "1.1.0", "", v2},
+		{"tag", "git+" + url + "@v1.0.0", "1.0.0", "v1.0.0", v1},
+		{"branch", "git+" + url + "@main", "1.1.0", "main", v2},
+		{"commit", "git+" + url + "@" + v1, "1.0.0", v1, v1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Fetch(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			want := Lock{Name: "acme", Version: tt.version, Source: "git+" + url, Ref: tt.ref, Commit: tt.commit, Sum: f.Lock.Sum}
+			if f.Lock != want || f.Lock.Sum == "" {
+				t.Errorf("Lock = %+v, want %+v", f.Lock, want)
+			}
+			out, err := f.Render(f.Vars("app", "dev", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 1 || out[0].Path != "README.md" || out[0].Content != "# app from acme "+tt.version+"\n" {
+				t.Errorf("Render() = %+v", out)
+			}
+			if _, err := os.Stat(filepath.Join(f.Dir(), ".git")); !os.IsNotExist(err) {
+				t.Errorf("the checkout keeps its .git directory")
+			}
+		})
+	}
+
+	t.Run("locked", func(t *testing.T) {
+		f, err := Fetch("git+" + url + "@v1.0.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lock := f.Lock
+		f.Close()
+		if _, err := os.Stat(f.Dir()); !os.IsNotExist(err) {
+			t.Errorf("Close left the checkout at %s", f.Dir())
+		}
+
+		// Moving the tag does not change what the lock fetches.
+		run(t, work, "tag", "--force", "v1.0.0", v2)
+		run(t, work, "push", "--quiet", "--force", "--tags", bare)
+		f, err = FetchLocked(lock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if f.Lock != lock || f.Version != "1.0.0" {
+			t.Errorf("FetchLocked() = %+v, want %+v", f.Lock, lock)
+		}
+
+		lock.Sum = "changed"
+		if _, err := FetchLocked(lock); err == nil || !strings.Contains(err.Error(), "has changed since it was locked") {
+			t.Errorf("FetchLocked() with another sum: error = %v", err)
+		}
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		if _, err := Fetch("git+" + url + "@v9"); err == nil || !strings.Contains(err.Error(), "no ref v9") {
+			t.Errorf("Fetch() error = %v, want no ref", err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+// Package plugin finds and runs goscaf plugins: executables named
+// goscaf-<name> on PATH, which goscaf exposes as the subcommand <name>.
+//
+// goscaf runs a plugin in the project root with the subcommand's arguments
+// and writes a Request as JSON to its stdin. The plugin writes a Response as
+// JSON to its stdout and exits with status 0; anything it writes to stderr is
+// shown to the user. goscaf then applies the files and edits of the
+// response the way it applies its own, so --dry-run and rollback work the
+// same. A plugin that exits with another status changes nothing.
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/manifest"
+)
+
+// Prefix is the file name prefix that makes an executable a plugin.
+const Prefix = "goscaf-"
+
+// ProtocolVersion is the version of Request and Response. It changes only
+// when a change would break existing plugins.
+const ProtocolVersion = 1
+
+// Request is what goscaf sends a plugin on stdin.
+type Request struct {
+	Protocol      int    `json:"protocol"`
+	GoscafVersion string `json:"goscaf_version"`
+	// Args are the arguments after the subcommand name, unparsed.
+	Args []string `json:"args"`
+	// ProjectRoot is the absolute path of the project, or of the working
+	// directory outside a project.
+	ProjectRoot string `json:"project_root"`
+	// Manifest is the project's .goscaf.json, or null outside a project.
+	Manifest *manifest.Manifest `json:"manifest"`
+	// DryRun is set when the response will only be shown, not applied.
+	DryRun bool `json:"dry_run"`
+}
+
+// Response is what a plugin writes to stdout. Files are written before
+// edits are made, each in order.
+type Response struct {
+	Files []File `json:"files,omitempty"`
+	Edits []Edit `json:"edits,omitempty"`
+}
+
+// File is a file to write, with a path relative to the project root. An
+// existing file is only replaced if Overwrite is set; Go files are formatted
+// and keep the custom regions of the file they replace.
+type File struct {
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// Edit operations on existing Go files. Every one is idempotent.
+const (
+	// AppendStmt appends Code to the body of the function Func unless the
+	// body already has that statement.
+	AppendStmt = "append_stmt"
+	// RemoveCalls removes the statements of Func that call Code, e.g.
+	// "RegisterAuthRoutes".
+	RemoveCalls = "remove_calls"
+	// AppendDecl appends the declarations in Code to the file unless it
+	// already declares them.
+	AppendDecl = "append_decl"
+	// AddImports adds the Imports the file does not import yet, each a
+	// path, or a quoted path preceded by a name.
+	AddImports = "add_imports"
+)
+
+// Edit is an edit to an existing Go file, with a path relative to the
+// project root.
+type Edit struct {
+	Path    string   `json:"path"`
+	Op      string   `json:"op"`
+	Func    string   `json:"func,omitempty"`
+	Code    string   `json:"code,omitempty"`
+	Imports []string `json:"imports,omitempty"`
+}
+
+// Plugin is an executable found on PATH.
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Discover returns the plugins on PATH sorted by name. When several
+// directories hold a plugin of the same name, the first one wins, as it
+// would in the shell.
+func Discover() []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), Prefix)
+			if !ok || name == "" || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				if name, ok = strings.CutSuffix(name, ".exe"); !ok {
+					continue
+				}
+			}
+			path := filepath.Join(dir, entry.Name())
+			if seen[name] || !executable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
+
+// Run runs the plugin in req.ProjectRoot and returns its response. The
+// plugin's stderr goes to stderr.
+func (p Plugin) Run(req Request, stderr io.Writer) (*Response, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(p.Path, req.Args...)
+	cmd.Dir = req.ProjectRoot
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
+
+	var resp Response
+	dec := json.NewDecoder(&stdout)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&resp); err != nil {
+		if err == io.EOF {
+			return &resp, nil // nothing to apply
+		}
+		return nil, fmt.Errorf("plugin %s: invalid response: %w", p.Name, err)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,115 @@
+// Package regions handles the protected regions of generated files. A
+// region starts with a "// goscaf:begin <name>" line and ends with a
+// "// goscaf:end" line; the lines between them belong to the user and are
+// carried over whenever goscaf writes the file again.
+package regions
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	beginMarker = "// goscaf:begin "
+	endMarker   = "// goscaf:end"
+)
+
+// Begin and End return the marker lines of a region, indented by indent.
+func Begin(name, indent string) string { return indent + beginMarker + name + "\n" }
+func End(indent string) string         { return indent + endMarker + "\n" }
+
+// Empty returns an empty region.
+func Empty(name, indent string) string { return Begin(name, indent) + End(indent) }
+
+// region is a parsed region: the index of its begin and end lines and the
+// key it is matched by, its name and its occurrence among regions of that
+// name.
+type region struct {
+	key        string
+	begin, end int
+}
+
+func parse(lines []string) []region {
+	var regions []region
+	seen := map[string]int{}
+	for i := 0; i < len(lines); i++ {
+		name, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), beginMarker)
+		if !ok {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(trimmed, beginMarker) {
+				break // unterminated; the nested marker starts the next region
+			}
+			if trimmed == endMarker {
+				name = strings.TrimSpace(name)
+				regions = append(regions, region{key: name + "#" + strconv.Itoa(seen[name]), begin: i, end: j})
+				seen[name]++
+				i = j
+				break
+			}
+		}
+	}
+	return regions
+}
+
+func splitLines(s string) []string { return strings.SplitAfter(s, "\n") }
+
+// Strip empties every region, so files that differ only inside regions
+// compare equal.
+func Strip(src []byte) []byte {
+	lines := splitLines(string(src))
+	regions := parse(lines)
+	if len(regions) == 0 {
+		return src
+	}
+	var b strings.Builder
+	next := 0
+	for _, r := range regions {
+		b.WriteString(strings.Join(lines[next:r.begin+1], ""))
+		next = r.end
+	}
+	b.WriteString(strings.Join(lines[next:], ""))
+	return []byte(b.String())
+}
+
+// Restore returns generated with the contents of each region replaced by
+// the contents of the region with the same name in previous. It also
+// returns the names of non-empty regions of previous that generated has no
+// place for; their contents would be lost.
+func Restore(generated, previous string) (string, []string) {
+	old := splitLines(previous)
+	bodies := map[string]string{}
+	for _, r := range parse(old) {
+		bodies[r.key] = strings.Join(old[r.begin+1:r.end], "")
+	}
+	if len(bodies) == 0 {
+		return generated, nil
+	}
+
+	lines := splitLines(generated)
+	var b strings.Builder
+	next := 0
+	for _, r := range parse(lines) {
+		b.WriteString(strings.Join(lines[next:r.begin+1], ""))
+		if body, ok := bodies[r.key]; ok {
+			b.WriteString(body)
+			delete(bodies, r.key)
+		} else {
+			b.WriteString(strings.Join(lines[r.begin+1:r.end], ""))
+		}
+		next = r.end
+	}
+	b.WriteString(strings.Join(lines[next:], ""))
+
+	var lost []string
+	for key, body := range bodies {
+		if strings.TrimSpace(body) != "" {
+			lost = append(lost, key[:strings.LastIndex(key, "#")])
+		}
+	}
+	sort.Strings(lost)
+	return b.String(), lost
+}
@@ -0,0 +1,737 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDDL reads the CREATE TABLE statements of a SQL script written for
+// Postgres, MySQL or SQLite. Primary keys, foreign keys and unique
+// constraints declared inline, as table constraints or by a later ALTER
+// TABLE ... ADD are all recognized, as is CREATE UNIQUE INDEX; every other
+// statement is ignored.
+func ParseDDL(src string) ([]Table, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	index := map[string]int{}
+	for _, stmt := range splitStatements(toks) {
+		p := &ddlParser{toks: stmt}
+		switch {
+		case p.accept("CREATE"):
+			for p.accept("TEMP") || p.accept("TEMPORARY") || p.accept("UNLOGGED") {
+			}
+			if p.accept("UNIQUE") {
+				if name, cols := p.createIndex(); len(cols) == 1 {
+					if i, ok := index[name]; ok {
+						setUnique(&tables[i], cols[0])
+					}
+				}
+				continue
+			}
+			if !p.accept("TABLE") {
+				continue
+			}
+			t, err := p.createTable()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := index[t.Name]; dup {
+				return nil, fmt.Errorf("table %s is defined twice", t.Name)
+			}
+			index[t.Name] = len(tables)
+			tables = append(tables, t)
+		case p.accept("ALTER"):
+			if !p.accept("TABLE") {
+				continue
+			}
+			p.accept("ONLY")
+			p.ifNotExists()
+			name := p.qualifiedName()
+			if i, ok := index[name]; ok {
+				if err := p.alterTable(&tables[i]); err != nil {
+					return nil, fmt.Errorf("ALTER TABLE %s: %w", name, err)
+				}
+			}
+		}
+	}
+	return tables, nil
+}
+
+type tokKind int
+
+const (
+	tokWord tokKind = iota
+	tokQuoted
+	tokString
+	tokSymbol
+)
+
+type ddlToken struct {
+	kind tokKind
+	text string
+	line int
+}
+
+// tokenize splits SQL into words, quoted identifiers, string literals and
+// symbols, dropping comments.
+func tokenize(src string) ([]ddlToken, error) {
+	var toks []ddlToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(src[i:], "--"), c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '$' && dollarTag(src[i:]) != "":
+			// A Postgres dollar-quoted string such as a function body,
+			// whose semicolons do not end the statement.
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated %s", line, tag)
+			}
+			body := src[i+len(tag) : i+len(tag)+end]
+			toks = append(toks, ddlToken{tokString, body, line})
+			line += strings.Count(body, "\n")
+			i += 2*len(tag) + end
+		case c == '[' && strings.HasPrefix(src[i:], "[]"):
+			// Postgres array type suffix, not a bracketed identifier.
+			toks = append(toks, ddlToken{tokSymbol, "[]", line})
+			i += 2
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			j := i + 1
+			var b strings.Builder
+			for ; j < len(src); j++ {
+				if src[j] == closing {
+					if j+1 < len(src) && src[j+1] == closing && closing != ']' {
+						b.WriteByte(closing)
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, c)
+			}
+			kind := tokQuoted
+			if c == '\'' {
+				kind = tokString
+			}
+			toks = append(toks, ddlToken{kind, b.String(), line})
+			line += strings.Count(src[i:j], "\n")
+			i = j + 1
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, ddlToken{tokWord, src[i:j], line})
+			i = j
+		case isWordByte(c):
+			j := i
+			for j < len(src) && isWordByte(src[j]) {
+				j++
+			}
+			toks = append(toks, ddlToken{tokWord, src[i:j], line})
+			i = j
+		default:
+			toks = append(toks, ddlToken{tokSymbol, string(c), line})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+// dollarTag returns the opening $tag$ or $$ that src starts with, or "".
+func dollarTag(src string) string {
+	for j := 1; j < len(src); j++ {
+		c := src[j]
+		switch {
+		case c == '$':
+			return src[:j+1]
+		case c >= '0' && c <= '9' && j == 1:
+			return "" // a parameter such as $1
+		case !isWordByte(c):
+			return ""
+		}
+	}
+	return ""
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func splitStatements(toks []ddlToken) [][]ddlToken {
+	var stmts [][]ddlToken
+	start := 0
+	for i, t := range toks {
+		if t.kind == tokSymbol && t.text == ";" {
+			if i > start {
+				stmts = append(stmts, toks[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		stmts = append(stmts, toks[start:])
+	}
+	return stmts
+}
+
+type ddlParser struct {
+	toks []ddlToken
+	pos  int
+}
+
+func (p *ddlParser) peek() (ddlToken, bool) {
+	if p.pos >= len(p.toks) {
+		return ddlToken{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// accept consumes the next token if it is the given keyword.
+func (p *ddlParser) accept(keyword string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokWord && strings.EqualFold(t.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ddlParser) acceptSymbol(s string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokSymbol && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ddlParser) errorf(format string, args ...any) error {
+	line := 0
+	if t, ok := p.peek(); ok {
+		line = t.line
+	} else if len(p.toks) > 0 {
+		line = p.toks[len(p.toks)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *ddlParser) ifNotExists() {
+	if p.accept("IF") {
+		p.accept("NOT")
+		p.accept("EXISTS")
+	}
+}
+
+// name consumes an identifier.
+func (p *ddlParser) name() string {
+	t, ok := p.peek()
+	if !ok || (t.kind != tokWord && t.kind != tokQuoted) {
+		return ""
+	}
+	p.pos++
+	return t.text
+}
+
+// qualifiedName consumes a possibly schema-qualified name and returns its
+// last part.
+func (p *ddlParser) qualifiedName() string {
+	name := p.name()
+	for p.acceptSymbol(".") {
+		name = p.name()
+	}
+	return name
+}
+
+// group consumes a parenthesized group and returns the tokens inside it.
+func (p *ddlParser) group() ([]ddlToken, error) {
+	if !p.acceptSymbol("(") {
+		return nil, p.errorf("expected (")
+	}
+	start, depth := p.pos, 1
+	for ; p.pos < len(p.toks); p.pos++ {
+		t := p.toks[p.pos]
+		if t.kind != tokSymbol {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				p.pos++
+				return p.toks[start : p.pos-1], nil
+			}
+		}
+	}
+	return nil, p.errorf("unbalanced parentheses")
+}
+
+// nameList consumes "(a, b, ...)", ignoring index options such as lengths
+// and sort order.
+func (p *ddlParser) nameList() ([]string, error) {
+	inner, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, part := range splitTopLevel(inner) {
+		if len(part) > 0 && part[0].kind != tokSymbol {
+			names = append(names, part[0].text)
+		}
+	}
+	return names, nil
+}
+
+// splitTopLevel splits tokens on commas outside parentheses.
+func splitTopLevel(toks []ddlToken) [][]ddlToken {
+	var parts [][]ddlToken
+	start, depth := 0, 0
+	for i, t := range toks {
+		if t.kind != tokSymbol {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, toks[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, toks[start:])
+}
+
+func (p *ddlParser) createTable() (Table, error) {
+	p.ifNotExists()
+	t := Table{Name: p.qualifiedName()}
+	if t.Name == "" {
+		return t, p.errorf("expected table name")
+	}
+	if p.accept("AS") {
+		return t, p.errorf("CREATE TABLE %s AS SELECT is not supported", t.Name)
+	}
+	body, err := p.group()
+	if err != nil {
+		return t, fmt.Errorf("table %s: %w", t.Name, err)
+	}
+
+	for _, def := range splitTopLevel(body) {
+		d := &ddlParser{toks: def}
+		if len(def) == 0 {
+			continue
+		}
+		if d.tableConstraint(&t) {
+			continue
+		}
+		if err := d.column(&t); err != nil {
+			return t, fmt.Errorf("table %s: %w", t.Name, err)
+		}
+	}
+	return t, nil
+}
+
+// tableConstraint parses a table-level constraint or index definition. It
+// reports false if the definition is a column.
+func (p *ddlParser) tableConstraint(t *Table) bool {
+	start := p.pos
+	if p.accept("CONSTRAINT") {
+		p.name()
+	}
+	switch {
+	case p.accept("PRIMARY"):
+		p.accept("KEY")
+		cols, err := p.nameList()
+		if err != nil {
+			return true
+		}
+		setPrimaryKey(t, cols)
+	case p.accept("FOREIGN"):
+		p.accept("KEY")
+		if t, ok := p.peek(); ok && t.kind != tokSymbol {
+			p.name() // MySQL index name
+		}
+		cols, err := p.nameList()
+		if err != nil || !p.accept("REFERENCES") {
+			return true
+		}
+		ref := p.qualifiedName()
+		refCols := []string{"id"}
+		if next, ok := p.peek(); ok && next.kind == tokSymbol && next.text == "(" {
+			if refCols, err = p.nameList(); err != nil {
+				return true
+			}
+		}
+		for i, col := range cols {
+			if i < len(refCols) {
+				t.ForeignKeys = append(t.ForeignKeys, ForeignKey{Column: col, RefTable: ref, RefColumn: refCols[i]})
+			}
+		}
+	case p.accept("UNIQUE"):
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+		if t, ok := p.peek(); ok && t.kind != tokSymbol {
+			p.name() // MySQL index name
+		}
+		if cols, err := p.nameList(); err == nil && len(cols) == 1 {
+			setUnique(t, cols[0])
+		}
+	case p.accept("CHECK"), p.accept("EXCLUDE"), p.accept("FULLTEXT"), p.accept("SPATIAL"):
+	case p.isIndex() && (p.accept("KEY") || p.accept("INDEX")):
+	default:
+		p.pos = start
+		return false
+	}
+	return true
+}
+
+// isIndex reports whether the definition is a MySQL "KEY [name] (cols)" or
+// "INDEX [name] (cols)" rather than a column that happens to be called key.
+func (p *ddlParser) isIndex() bool {
+	for _, i := range []int{p.pos + 1, p.pos + 2} {
+		if i < len(p.toks) && p.toks[i].kind == tokSymbol && p.toks[i].text == "(" {
+			return i == p.pos+1 || p.toks[i-1].kind != tokWord || !isTypeName(p.toks[i-1].text)
+		}
+	}
+	return false
+}
+
+// isTypeName reports whether a word is a parameterized SQL type, such as
+// varchar in "key varchar(255)".
+func isTypeName(word string) bool {
+	switch strings.ToLower(word) {
+	case "char", "character", "varchar", "nvarchar", "nchar", "binary", "varbinary",
+		"decimal", "numeric", "float", "double", "bit", "int", "integer", "bigint",
+		"smallint", "tinyint", "mediumint", "timestamp", "datetime", "time", "enum", "set":
+		return true
+	}
+	return false
+}
+
+// createIndex parses the rest of "CREATE UNIQUE INDEX [name] ON table
+// (cols)" and returns the table and its columns. An index on expressions
+// returns no columns.
+func (p *ddlParser) createIndex() (string, []string) {
+	if !p.accept("INDEX") {
+		return "", nil
+	}
+	p.accept("CONCURRENTLY")
+	p.ifNotExists()
+	if !p.accept("ON") {
+		p.qualifiedName()
+		if !p.accept("ON") {
+			return "", nil
+		}
+	}
+	p.accept("ONLY")
+	table := p.qualifiedName()
+	if p.accept("USING") {
+		p.name()
+	}
+	start := p.pos
+	inner, err := p.group()
+	if err != nil {
+		return "", nil
+	}
+	for _, t := range inner {
+		if t.kind == tokSymbol && t.text == "(" {
+			return "", nil
+		}
+	}
+	p.pos = start
+	cols, _ := p.nameList()
+	return table, cols
+}
+
+// setUnique marks a column as unique on its own.
+func setUnique(t *Table, col string) {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, col) {
+			t.Columns[i].Unique = true
+		}
+	}
+}
+
+func setPrimaryKey(t *Table, cols []string) {
+	for _, name := range cols {
+		for i := range t.Columns {
+			if strings.EqualFold(t.Columns[i].Name, name) {
+				t.Columns[i].PrimaryKey = true
+				t.Columns[i].Nullable = false
+			}
+		}
+	}
+	markRowID(t)
+}
+
+// markRowID marks a single INTEGER PRIMARY KEY column as assigned by the
+// database, since SQLite makes it an alias of the rowid.
+func markRowID(t *Table) {
+	pk := -1
+	for i, c := range t.Columns {
+		if c.PrimaryKey {
+			if pk >= 0 {
+				return
+			}
+			pk = i
+		}
+	}
+	if pk >= 0 && strings.EqualFold(t.Columns[pk].Type, "integer") {
+		t.Columns[pk].AutoIncrement = true
+	}
+}
+
+// columnKeywords start a column constraint and so end the column type.
+var columnKeywords = map[string]bool{
+	"CONSTRAINT": true, "NOT": true, "NULL": true, "PRIMARY": true, "UNIQUE": true,
+	"DEFAULT": true, "REFERENCES": true, "CHECK": true, "COLLATE": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "GENERATED": true, "IDENTITY": true,
+	"COMMENT": true, "ON": true, "KEY": true, "CHARACTER": true, "CHARSET": true,
+	"AS": true, "STORED": true, "VIRTUAL": true,
+}
+
+func (p *ddlParser) column(t *Table) error {
+	c := Column{Name: p.name(), Nullable: true}
+	if c.Name == "" {
+		return p.errorf("expected column name")
+	}
+
+	var typ []string
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokWord && columnKeywords[strings.ToUpper(tok.text)] && (len(typ) > 0 || !strings.EqualFold(tok.text, "character")) {
+			break
+		}
+		if tok.kind == tokSymbol && tok.text == "(" {
+			inner, err := p.group()
+			if err != nil {
+				return err
+			}
+			typ[len(typ)-1] += "(" + joinTokens(inner) + ")"
+			continue
+		}
+		if tok.kind == tokSymbol && tok.text == "[]" && len(typ) > 0 {
+			typ[len(typ)-1] += "[]"
+			p.pos++
+			continue
+		}
+		typ = append(typ, tok.text)
+		p.pos++
+	}
+	c.Type = strings.Join(typ, " ")
+	if c.Type == "" {
+		// SQLite allows columns without a type.
+		c.Type = "text"
+	}
+	c.Size = TypeSize(c.Type)
+	switch strings.ToLower(typ0(c.Type)) {
+	case "serial", "bigserial", "smallserial", "serial4", "serial8", "serial2":
+		c.AutoIncrement = true
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok {
+			break
+		}
+		switch {
+		case p.accept("CONSTRAINT"):
+			p.name()
+		case p.accept("NOT"):
+			p.accept("NULL")
+			c.Nullable = false
+		case p.accept("NULL"):
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			c.Unique = true
+		case p.accept("PRIMARY"):
+			p.accept("KEY")
+			p.accept("ASC")
+			p.accept("DESC")
+			c.PrimaryKey, c.Nullable = true, false
+		case p.accept("AUTO_INCREMENT"), p.accept("AUTOINCREMENT"):
+			c.AutoIncrement = true
+		case p.accept("IDENTITY"):
+			c.AutoIncrement = true
+			if next, ok := p.peek(); ok && next.kind == tokSymbol && next.text == "(" {
+				p.group()
+			}
+		case p.accept("GENERATED"):
+			if p.accept("ALWAYS") || p.accept("BY") {
+				p.accept("DEFAULT")
+			}
+			if p.accept("AS") {
+				if p.accept("IDENTITY") {
+					c.AutoIncrement = true
+				}
+				if next, ok := p.peek(); ok && next.kind == tokSymbol && next.text == "(" {
+					p.group()
+				}
+			}
+		case p.accept("DEFAULT"):
+			c.Default = p.expression()
+			if strings.HasPrefix(strings.ToLower(c.Default), "nextval(") {
+				c.AutoIncrement = true
+			}
+		case p.accept("REFERENCES"):
+			ref := p.qualifiedName()
+			refCol := "id"
+			if next, ok := p.peek(); ok && next.kind == tokSymbol && next.text == "(" {
+				cols, err := p.nameList()
+				if err != nil {
+					return err
+				}
+				if len(cols) > 0 {
+					refCol = cols[0]
+				}
+			}
+			t.ForeignKeys = append(t.ForeignKeys, ForeignKey{Column: c.Name, RefTable: ref, RefColumn: refCol})
+		case p.accept("CHECK"), p.accept("AS"):
+			p.group()
+		case p.accept("ON"):
+			// ON UPDATE / ON DELETE actions and MySQL's ON UPDATE CURRENT_TIMESTAMP.
+			p.pos++
+			p.expression()
+		case tok.kind == tokSymbol && tok.text == "(":
+			p.group()
+		default:
+			// COLLATE, COMMENT, CHARACTER SET and similar options take one
+			// argument that does not matter here.
+			p.pos++
+		}
+	}
+
+	t.Columns = append(t.Columns, c)
+	if c.PrimaryKey {
+		markRowID(t)
+	}
+	return nil
+}
+
+// expression consumes a default value: a single token, a parenthesized
+// group, or a function call, with any ::type casts.
+func (p *ddlParser) expression() string {
+	var parts []string
+	tok, ok := p.peek()
+	if !ok {
+		return ""
+	}
+	switch {
+	case tok.kind == tokSymbol && tok.text == "(":
+		inner, _ := p.group()
+		parts = append(parts, "("+joinTokens(inner)+")")
+	case tok.kind == tokSymbol && tok.text == "-":
+		p.pos++
+		return "-" + p.expression()
+	case tok.kind == tokString:
+		p.pos++
+		parts = append(parts, "'"+strings.ReplaceAll(tok.text, "'", "''")+"'")
+	default:
+		p.pos++
+		parts = append(parts, tok.text)
+		if next, ok := p.peek(); ok && next.kind == tokSymbol && next.text == "(" {
+			inner, _ := p.group()
+			parts[0] += "(" + joinTokens(inner) + ")"
+		}
+	}
+	for p.acceptSymbol(":") && p.acceptSymbol(":") {
+		cast := p.name()
+		// Multi-word types such as character varying.
+		for next, ok := p.peek(); ok && next.kind == tokWord && !columnKeywords[strings.ToUpper(next.text)]; next, ok = p.peek() {
+			cast += " " + next.text
+			p.pos++
+		}
+		parts = append(parts, "::"+cast)
+	}
+	return strings.Join(parts, "")
+}
+
+func (p *ddlParser) alterTable(t *Table) error {
+	for _, action := range splitTopLevel(p.toks[p.pos:]) {
+		a := &ddlParser{toks: action}
+		switch {
+		case a.accept("ADD"):
+			if a.accept("COLUMN") {
+				a.ifNotExists()
+			} else if a.tableConstraint(t) {
+				continue
+			}
+			if err := a.column(t); err != nil {
+				return err
+			}
+		case a.accept("ALTER"):
+			// pg_dump attaches sequences and identities to key columns here.
+			a.accept("COLUMN")
+			name := a.name()
+			for i := range t.Columns {
+				if t.Columns[i].Name != name {
+					continue
+				}
+				if a.accept("SET") && a.accept("DEFAULT") {
+					t.Columns[i].Default = a.expression()
+					if strings.HasPrefix(strings.ToLower(t.Columns[i].Default), "nextval(") {
+						t.Columns[i].AutoIncrement = true
+					}
+				} else if a.accept("ADD") && a.accept("GENERATED") {
+					t.Columns[i].AutoIncrement = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func joinTokens(toks []ddlToken) string {
+	var b strings.Builder
+	for i, t := range toks {
+		if i > 0 && t.kind != tokSymbol && toks[i-1].kind != tokSymbol {
+			b.WriteByte(' ')
+		}
+		if t.kind == tokString {
+			b.WriteString("'" + strings.ReplaceAll(t.text, "'", "''") + "'")
+			continue
+		}
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+func typ0(typ string) string {
+	word, _, _ := strings.Cut(typ, " ")
+	word, _, _ = strings.Cut(word, "(")
+	return word
+}
@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDDL(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Table
+	}{
+		{
+			name: "postgres inline constraints",
+			src: `CREATE TABLE IF NOT EXISTS public.users (
+				id SERIAL PRIMARY KEY,
+				email VARCHAR(255) NOT NULL UNIQUE,
+				status character varying(16) DEFAULT 'active'::character varying,
+				tags text[]
+			);
+			CREATE TABLE posts (
+				id BIGSERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+			);`,
+			want: []Table{
+				{Name: "users", Columns: []Column{
+					{Name: "id", Type: "SERIAL", PrimaryKey: true, AutoIncrement: true},
+					{Name: "email", Type: "VARCHAR(255)", Unique: true, Size: 255},
+					{Name: "status", Type: "character varying(16)", Nullable: true, Default: "'active'::character varying", Size: 16},
+					{Name: "tags", Type: "text[]", Nullable: true},
+				}},
+				{Name: "posts", Columns: []Column{
+					{Name: "id", Type: "BIGSERIAL", PrimaryKey: true, AutoIncrement: true},
+					{Name: "user_id", Type: "INTEGER"},
+					{Name: "created_at", Type: "TIMESTAMP WITH TIME ZONE", Nullable: true, Default: "now()"},
+				}, ForeignKeys: []ForeignKey{{Column: "user_id", RefTable: "users", RefColumn: "id"}}},
+			},
+		},
+		{
+			name: "mysql backticks and table constraints",
+			src: "CREATE TABLE `order items` (\n" +
+				"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `key` varchar(64) NOT NULL COMMENT 'lookup key',\n" +
+				"  `order_id` bigint(20) NOT NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `uk_key` (`key`),\n" +
+				"  KEY `idx_order` (`order_id`),\n" +
+				"  CONSTRAINT `fk_order` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			want: []Table{
+				{Name: "order items", Columns: []Column{
+					{Name: "id", Type: "int(11) unsigned", PrimaryKey: true, AutoIncrement: true},
+					{Name: "key", Type: "varchar(64)", Unique: true, Size: 64},
+					{Name: "order_id", Type: "bigint(20)"},
+				}, ForeignKeys: []ForeignKey{{Column: "order_id", RefTable: "orders", RefColumn: "id"}}},
+			},
+		},
+		{
+			name: "sqlite rowid and untyped column",
+			src: `-- a comment; with a semicolon
+			CREATE TABLE notes (id INTEGER PRIMARY KEY, body, /* inline; */ pinned BOOLEAN DEFAULT 0);`,
+			want: []Table{
+				{Name: "notes", Columns: []Column{
+					{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true},
+					{Name: "body", Type: "text", Nullable: true},
+					{Name: "pinned", Type: "BOOLEAN", Nullable: true, Default: "0"},
+				}},
+			},
+		},
+		{
+			name: "dollar-quoted bodies are not statements",
+			src: `CREATE FUNCTION touch() RETURNS trigger AS $$
+			BEGIN
+				CREATE TABLE IF NOT EXISTS audit (id int);
+				NEW.updated_at = now();
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql;
+			DO $body$ BEGIN CREATE TABLE scratch (id int); END $body$;
+			CREATE TABLE items (id integer PRIMARY KEY, price numeric(10,2) DEFAULT 0);`,
+			want: []Table{
+				{Name: "items", Columns: []Column{
+					{Name: "id", Type: "integer", PrimaryKey: true, AutoIncrement: true},
+					{Name: "price", Type: "numeric(10,2)", Nullable: true, Default: "0"},
+				}},
+			},
+		},
+		{
+			name: "pg_dump keys added by ALTER TABLE",
+			src: `CREATE TABLE public.accounts (
+				id integer NOT NULL,
+				handle text NOT NULL,
+				owner_id integer
+			);
+			CREATE SEQUENCE public.accounts_id_seq AS integer START WITH 1;
+			ALTER TABLE ONLY public.accounts ALTER COLUMN id SET DEFAULT nextval('public.accounts_id_seq'::regclass);
+			ALTER TABLE ONLY public.accounts
+				ADD CONSTRAINT accounts_pkey PRIMARY KEY (id);
+			ALTER TABLE ONLY public.accounts
+				ADD CONSTRAINT accounts_owner_fkey FOREIGN KEY (owner_id) REFERENCES public.accounts(id);
+			CREATE UNIQUE INDEX accounts_handle_key ON public.accounts USING btree (handle);
+			CREATE UNIQUE INDEX accounts_lower_handle ON public.accounts (lower(handle));`,
+			want: []Table{
+				{Name: "accounts", Columns: []Column{
+					{Name: "id", Type: "integer", PrimaryKey: true, AutoIncrement: true, Default: "nextval('public.accounts_id_seq'::regclass)"},
+					{Name: "handle", Type: "text", Unique: true},
+					{Name: "owner_id", Type: "integer", Nullable: true},
+				}, ForeignKeys: []ForeignKey{{Column: "owner_id", RefTable: "accounts", RefColumn: "id"}}},
+			},
+		},
+		{
+			name: "composite primary key",
+			src: `CREATE TABLE book_tags (
+				book_id INTEGER NOT NULL REFERENCES books,
+				tag TEXT NOT NULL,
+				PRIMARY KEY (book_id, tag),
+				UNIQUE (book_id, tag)
+			);`,
+			want: []Table{
+				{Name: "book_tags", Columns: []Column{
+					{Name: "book_id", Type: "INTEGER", PrimaryKey: true},
+					{Name: "tag", Type: "TEXT", PrimaryKey: true},
+				}, ForeignKeys: []ForeignKey{{Column: "book_id", RefTable: "books", RefColumn: "id"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDDL(tt.src)
+			if err != nil {
+				t.Fatalf("ParseDDL() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDDL() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDDLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"duplicate table", "CREATE TABLE a (id int); CREATE TABLE a (id int);", "table a is defined twice"},
+		{"unterminated string", "CREATE TABLE a (s text DEFAULT 'x);", "line 1: unterminated '"},
+		{"unterminated dollar quote", "CREATE TABLE a (id int);\nDO $$ BEGIN END;", "line 2: unterminated $$"},
+		{"unterminated comment", "CREATE TABLE a (id int); /* no end", "line 1: unterminated comment"},
+		{"create table as select", "CREATE TABLE a AS SELECT 1;", "CREATE TABLE a AS SELECT is not supported"},
+		{"unbalanced parentheses", "CREATE TABLE a (id int,\n b numeric(10, 2);", "unbalanced parentheses"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDDL(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseDDL() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
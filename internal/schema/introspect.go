@@ -0,0 +1,304 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// DriverFromDSN guesses the database driver from a DSN: postgres:// and
+// postgresql:// URLs and key=value strings are Postgres, everything else is
+// treated as a SQLite file path or file: URI.
+func DriverFromDSN(dsn string) string {
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"),
+		strings.Contains(dsn, "host=") || strings.Contains(dsn, "dbname="):
+		return "postgres"
+	default:
+		return "sqlite"
+	}
+}
+
+// Introspect reads every user table of a SQLite or Postgres database,
+// including columns, primary keys, foreign keys and unique constraints.
+func Introspect(driver, dsn string) ([]Table, error) {
+	switch driver {
+	case "sqlite":
+		dsn = strings.TrimPrefix(dsn, "sqlite://")
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		return introspectSQLite(db)
+	case "postgres":
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		return introspectPostgres(db)
+	}
+	return nil, fmt.Errorf("unsupported driver %q (expected sqlite or postgres)", driver)
+}
+
+func introspectSQLite(db *sql.DB) ([]Table, error) {
+	rows, err := db.Query(`SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT IN ('schema_migrations', 'goose_db_version')
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	for _, name := range names {
+		table := Table{Name: name}
+
+		cols, err := db.Query(fmt.Sprintf("PRAGMA table_info(%q)", name))
+		if err != nil {
+			return nil, err
+		}
+		var pks int
+		for cols.Next() {
+			var cid, notNull, pk int
+			var name, typ string
+			var dflt sql.NullString
+			if err := cols.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+				cols.Close()
+				return nil, err
+			}
+			if pk > 0 {
+				pks++
+			}
+			table.Columns = append(table.Columns, Column{
+				Name:       name,
+				Type:       typ,
+				Nullable:   notNull == 0 && pk == 0,
+				PrimaryKey: pk > 0,
+				Default:    dflt.String,
+				Size:       TypeSize(typ),
+			})
+		}
+		cols.Close()
+
+		// A single INTEGER PRIMARY KEY column aliases the rowid and is assigned automatically.
+		for i, c := range table.Columns {
+			if c.PrimaryKey && pks == 1 && strings.EqualFold(c.Type, "integer") {
+				table.Columns[i].AutoIncrement = true
+			}
+		}
+
+		unique, err := sqliteUniqueColumns(db, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range unique {
+			setUnique(&table, col)
+		}
+
+		fks, err := db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%q)", name))
+		if err != nil {
+			return nil, err
+		}
+		for fks.Next() {
+			var id, seq int
+			var refTable, from string
+			var to, onUpdate, onDelete, match sql.NullString
+			if err := fks.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+				fks.Close()
+				return nil, err
+			}
+			ref := to.String
+			if ref == "" {
+				ref = "id"
+			}
+			table.ForeignKeys = append(table.ForeignKeys, ForeignKey{Column: from, RefTable: refTable, RefColumn: ref})
+		}
+		fks.Close()
+
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// sqliteUniqueColumns returns the columns of a table that a unique
+// constraint or index covers on their own.
+func sqliteUniqueColumns(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA index_list(%q)", table))
+	if err != nil {
+		return nil, err
+	}
+	var indexes []string
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if unique == 1 && origin != "pk" && partial == 0 {
+			indexes = append(indexes, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var cols []string
+	for _, index := range indexes {
+		info, err := db.Query(fmt.Sprintf("PRAGMA index_info(%q)", index))
+		if err != nil {
+			return nil, err
+		}
+		var names []sql.NullString
+		for info.Next() {
+			var seqno, cid int
+			var name sql.NullString
+			if err := info.Scan(&seqno, &cid, &name); err != nil {
+				info.Close()
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		info.Close()
+		if len(names) == 1 && names[0].Valid {
+			cols = append(cols, names[0].String)
+		}
+	}
+	return cols, nil
+}
+
+func introspectPostgres(db *sql.DB) ([]Table, error) {
+	rows, err := db.Query(`SELECT table_name FROM information_schema.tables
+		WHERE table_schema = 'public' AND table_type = 'BASE TABLE' AND table_name NOT IN ('schema_migrations', 'goose_db_version')
+		ORDER BY table_name`)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	for _, name := range names {
+		table := Table{Name: name}
+
+		pks := map[string]bool{}
+		pkRows, err := db.Query(`SELECT kcu.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+			  ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+			WHERE tc.table_schema = 'public' AND tc.table_name = $1 AND tc.constraint_type = 'PRIMARY KEY'`, name)
+		if err != nil {
+			return nil, err
+		}
+		for pkRows.Next() {
+			var col string
+			if err := pkRows.Scan(&col); err != nil {
+				pkRows.Close()
+				return nil, err
+			}
+			pks[col] = true
+		}
+		pkRows.Close()
+
+		cols, err := db.Query(`SELECT column_name, data_type, is_nullable, COALESCE(column_default, ''),
+			  COALESCE(character_maximum_length, 0)
+			FROM information_schema.columns
+			WHERE table_schema = 'public' AND table_name = $1
+			ORDER BY ordinal_position`, name)
+		if err != nil {
+			return nil, err
+		}
+		for cols.Next() {
+			var col, typ, nullable, dflt string
+			var size int
+			if err := cols.Scan(&col, &typ, &nullable, &dflt, &size); err != nil {
+				cols.Close()
+				return nil, err
+			}
+			table.Columns = append(table.Columns, Column{
+				Name:          col,
+				Type:          typ,
+				Nullable:      nullable == "YES",
+				PrimaryKey:    pks[col],
+				AutoIncrement: strings.HasPrefix(dflt, "nextval(") || strings.Contains(dflt, "identity"),
+				Default:       dflt,
+				Size:          size,
+			})
+		}
+		cols.Close()
+
+		// Unique constraints and unique indexes on a single column, without
+		// expressions or a WHERE clause.
+		uniqueRows, err := db.Query(`SELECT a.attname
+			FROM pg_index i
+			JOIN pg_class c ON c.oid = i.indrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = i.indkey[0]
+			WHERE n.nspname = 'public' AND c.relname = $1 AND i.indisunique AND NOT i.indisprimary
+			  AND i.indnatts = 1 AND i.indexprs IS NULL AND i.indpred IS NULL`, name)
+		if err != nil {
+			return nil, err
+		}
+		for uniqueRows.Next() {
+			var col string
+			if err := uniqueRows.Scan(&col); err != nil {
+				uniqueRows.Close()
+				return nil, err
+			}
+			setUnique(&table, col)
+		}
+		uniqueRows.Close()
+
+		fkRows, err := db.Query(`SELECT kcu.column_name, ccu.table_name, ccu.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+			  ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+			JOIN information_schema.constraint_column_usage ccu
+			  ON tc.constraint_name = ccu.constraint_name AND tc.table_schema = ccu.table_schema
+			WHERE tc.table_schema = 'public' AND tc.table_name = $1 AND tc.constraint_type = 'FOREIGN KEY'`, name)
+		if err != nil {
+			return nil, err
+		}
+		for fkRows.Next() {
+			var fk ForeignKey
+			if err := fkRows.Scan(&fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+				fkRows.Close()
+				return nil, err
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		fkRows.Close()
+
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
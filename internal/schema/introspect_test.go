@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIntrospectSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE authors (
+			id INTEGER PRIMARY KEY,
+			email VARCHAR(120) NOT NULL UNIQUE,
+			bio TEXT,
+			joined TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE books (
+			id INTEGER PRIMARY KEY,
+			author_id INTEGER NOT NULL REFERENCES authors (id),
+			price NUMERIC(10,2) DEFAULT 0,
+			ratio DOUBLE PRECISION,
+			loan INTERVAL,
+			cover BLOB,
+			meta JSON
+		);
+		CREATE UNIQUE INDEX books_cover ON books (cover);
+		CREATE TABLE book_tags (
+			book_id BIGINT NOT NULL REFERENCES books,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (book_id, tag)
+		);
+		CREATE TABLE schema_migrations (version BIGINT PRIMARY KEY);
+		CREATE TABLE goose_db_version (id INTEGER PRIMARY KEY, version_id BIGINT);`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tables, err := Introspect(DriverFromDSN("sqlite://"+path), "sqlite://"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Table{
+		{Name: "authors", Columns: []Column{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true},
+			{Name: "email", Type: "VARCHAR(120)", Unique: true, Size: 120},
+			{Name: "bio", Type: "TEXT", Nullable: true},
+			{Name: "joined", Type: "TIMESTAMP", Nullable: true, Default: "CURRENT_TIMESTAMP"},
+		}},
+		{Name: "book_tags", Columns: []Column{
+			{Name: "book_id", Type: "BIGINT", PrimaryKey: true},
+			{Name: "tag", Type: "TEXT", PrimaryKey: true},
+		}, ForeignKeys: []ForeignKey{{Column: "book_id", RefTable: "books", RefColumn: "id"}}},
+		{Name: "books", Columns: []Column{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true},
+			{Name: "author_id", Type: "INTEGER"},
+			{Name: "price", Type: "NUMERIC(10,2)", Nullable: true, Default: "0"},
+			{Name: "ratio", Type: "DOUBLE PRECISION", Nullable: true},
+			{Name: "loan", Type: "INTERVAL", Nullable: true},
+			{Name: "cover", Type: "BLOB", Nullable: true, Unique: true},
+			{Name: "meta", Type: "JSON", Nullable: true},
+		}, ForeignKeys: []ForeignKey{{Column: "author_id", RefTable: "authors", RefColumn: "id"}}},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Fatalf("Introspect() =\n%+v\nwant\n%+v", tables, want)
+	}
+
+	goTypes := map[string]string{
+		"authors.id": "int64", "authors.email": "string", "authors.bio": "string", "authors.joined": "time.Time",
+		"book_tags.book_id": "int64", "book_tags.tag": "string",
+		"books.id": "int64", "books.author_id": "int64", "books.price": "decimal.Decimal",
+		"books.ratio": "float64", "books.loan": "string", "books.cover": "[]byte", "books.meta": "json.RawMessage",
+	}
+	for _, table := range tables {
+		for _, c := range table.Columns {
+			if got, want := GoType(c.Type), goTypes[table.Name+"."+c.Name]; got != want {
+				t.Errorf("GoType(%q) for %s.%s = %s, want %s", c.Type, table.Name, c.Name, got, want)
+			}
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	tests := map[string]string{
+		"interval":                    "string",
+		"point":                       "string",
+		"character varying(64)":       "string",
+		"bigint(20) unsigned":         "int64",
+		"tinyint(1)":                  "bool",
+		"tinyint(4)":                  "int",
+		"timestamp(3) with time zone": "time.Time",
+		"double precision":            "float64",
+		"integer[]":                   "json.RawMessage",
+		"uuid":                        "uuid.UUID",
+	}
+	for sqlType, want := range tests {
+		if got := GoType(sqlType); got != want {
+			t.Errorf("GoType(%q) = %s, want %s", sqlType, got, want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Table is a database table discovered by introspection or parsed from DDL.
+type Table struct {
+	Name        string
+	Columns     []Column
+	ForeignKeys []ForeignKey
+}
+
+// Column is a single table column.
+type Column struct {
+	Name          string
+	Type          string
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	Unique        bool   // a unique constraint or index on this column alone
+	Default       string // the default as written in SQL, e.g. 'active' or now()
+	Size          int    // the length of a character type, e.g. 255 for VARCHAR(255)
+}
+
+// ForeignKey links a column to a column of another table.
+type ForeignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// ForeignKey returns the foreign key defined on a column, if any.
+func (t Table) ForeignKey(column string) (ForeignKey, bool) {
+	for _, fk := range t.ForeignKeys {
+		if fk.Column == column {
+			return fk, true
+		}
+	}
+	return ForeignKey{}, false
+}
+
+// TypeSize returns the length of a character type such as VARCHAR(255) or
+// character varying(64), or 0 for any other type.
+func TypeSize(sqlType string) int {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	open := strings.IndexByte(t, '(')
+	if open < 0 || !strings.HasSuffix(t, ")") {
+		return 0
+	}
+	switch strings.TrimSpace(t[:open]) {
+	case "varchar", "character varying", "char", "character", "nvarchar", "nchar", "varchar2", "nvarchar2":
+		n, err := strconv.Atoi(strings.TrimSpace(t[open+1 : len(t)-1]))
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+	return 0
+}
+
+// goTypes maps the SQL type names of every supported dialect, lower-cased
+// and without a length or precision, to Go types. Names not listed map to
+// string.
+var goTypes = map[string]string{
+	"bool": "bool", "boolean": "bool", "bit": "bool",
+
+	// SQLite stores every INTEGER as 64 bits.
+	"integer": "int64", "bigint": "int64", "int8": "int64", "big int": "int64", "bigserial": "int64", "serial8": "int64",
+	"int": "int", "int2": "int", "int4": "int", "smallint": "int", "tinyint": "int", "mediumint": "int",
+	"serial": "int", "serial4": "int", "smallserial": "int", "serial2": "int", "year": "int",
+
+	"uuid": "uuid.UUID", "uniqueidentifier": "uuid.UUID",
+	"json": "json.RawMessage", "jsonb": "json.RawMessage",
+	"numeric": "decimal.Decimal", "decimal": "decimal.Decimal", "dec": "decimal.Decimal",
+	"money": "decimal.Decimal", "smallmoney": "decimal.Decimal",
+	"real": "float64", "float": "float64", "float4": "float64", "float8": "float64",
+	"double": "float64", "double precision": "float64",
+
+	"date": "time.Time", "time": "time.Time", "timetz": "time.Time", "timestamp": "time.Time",
+	"timestamptz": "time.Time", "datetime": "time.Time", "datetime2": "time.Time",
+	"smalldatetime": "time.Time", "datetimeoffset": "time.Time",
+	"time with time zone": "time.Time", "time without time zone": "time.Time",
+	"timestamp with time zone": "time.Time", "timestamp without time zone": "time.Time",
+
+	"blob": "[]byte", "tinyblob": "[]byte", "mediumblob": "[]byte", "longblob": "[]byte",
+	"bytea": "[]byte", "binary": "[]byte", "varbinary": "[]byte", "image": "[]byte",
+}
+
+// GoType maps a SQL column type from any supported dialect to a Go type,
+// by its whole name: interval and point are strings, not integers.
+func GoType(sqlType string) string {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	if strings.HasSuffix(t, "[]") {
+		return "json.RawMessage"
+	}
+	if strings.HasPrefix(t, "tinyint(1)") {
+		return "bool"
+	}
+
+	// Drop lengths and precisions, wherever they are, and MySQL's sign
+	// modifiers: timestamp(3) with time zone, bigint(20) unsigned.
+	var b strings.Builder
+	depth := 0
+	for _, r := range t {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	var words []string
+	for _, w := range strings.Fields(b.String()) {
+		if w != "unsigned" && w != "signed" && w != "zerofill" {
+			words = append(words, w)
+		}
+	}
+
+	if goType, ok := goTypes[strings.Join(words, " ")]; ok {
+		return goType
+	}
+	return "string"
+}
+
+// SortByDependencies orders tables so every table comes after the tables its
+// foreign keys reference. Cycles and self references keep their input order.
+func SortByDependencies(tables []Table) []Table {
+	byName := make(map[string]Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+
+	sorted := make([]Table, 0, len(tables))
+	state := map[string]int{} // 1 = visiting, 2 = done
+	var visit func(t Table)
+	visit = func(t Table) {
+		if state[t.Name] != 0 {
+			return
+		}
+		state[t.Name] = 1
+		for _, fk := range t.ForeignKeys {
+			if ref, ok := byName[fk.RefTable]; ok && fk.RefTable != t.Name {
+				visit(ref)
+			}
+		}
+		state[t.Name] = 2
+		sorted = append(sorted, t)
+	}
+	for _, t := range tables {
+		visit(t)
+	}
+	return sorted
+}
@@ -22,7 +22,18 @@ func main() {
 		return nil
 	})
 
+	RootCmd.Version = generator.Version
 	RootCmd.AddCommand(generator.InitCmd)
+	RootCmd.AddCommand(generator.MigrateCmd)
+	RootCmd.AddCommand(generator.GenerateCmd)
+	RootCmd.AddCommand(generator.DestroyCmd)
+	RootCmd.AddCommand(generator.UpgradeCmd)
+	RootCmd.AddCommand(generator.CheckCmd)
+	RootCmd.AddCommand(generator.DoctorCmd)
+	RootCmd.AddCommand(generator.ListCmd)
+	RootCmd.AddCommand(generator.PackCmd)
+	RootCmd.AddCommand(generator.RenderCmd)
+	generator.AddPlugins(RootCmd)
 
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -0,0 +1,445 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// APIType is a DTO generated from an OpenAPI schema. Alias is set for
+// schemas that are not objects, e.g. "[]Pet" or "string".
+type APIType struct {
+	Name   string
+	Doc    string
+	Alias  string
+	Fields []APIField
+}
+
+// APIField is a property of an object DTO.
+type APIField struct {
+	Name     string
+	JSON     string
+	Type     string
+	Required bool
+}
+
+// APIParam is a path or query parameter of an operation.
+type APIParam struct {
+	Name     string
+	Var      string
+	In       string
+	Type     string
+	Required bool
+}
+
+// APIOperation is an OpenAPI operation. Body and Response are DTO types, or
+// empty when the operation has none.
+type APIOperation struct {
+	Name     string
+	Method   string
+	Path     string
+	Summary  string
+	Params   []APIParam
+	Body     string
+	Response string
+	Status   int
+}
+
+// dtoType qualifies the DTO names in a type with the dto package,
+// e.g. "[]Pet" becomes "[]dto.Pet".
+func dtoType(t string) string {
+	prefix := ""
+	for {
+		switch {
+		case strings.HasPrefix(t, "[]"), strings.HasPrefix(t, "*"):
+			n := 1
+			if t[0] == '[' {
+				n = 2
+			}
+			prefix, t = prefix+t[:n], t[n:]
+			continue
+		case strings.HasPrefix(t, "map[string]"):
+			prefix, t = prefix+"map[string]", t[len("map[string]"):]
+			continue
+		}
+		break
+	}
+	if t != "" && unicode.IsUpper(rune(t[0])) {
+		t = "dto." + t
+	}
+	return prefix + t
+}
+
+// usesDTO reports whether a type refers to the dto package.
+func usesDTO(t string) bool { return strings.Contains(dtoType(t), "dto.") }
+
+func apiTypeImports(module string, types ...string) []string {
+	var imports []string
+	for _, t := range types {
+		if strings.Contains(t, "time.Time") {
+			imports = append(imports, `"time"`)
+		}
+		if usesDTO(t) {
+			imports = append(imports, quote(module+"/internal/dto"))
+		}
+	}
+	return imports
+}
+
+// apiSignature returns the parameter list and results of a service method.
+func apiSignature(op APIOperation) (string, string) {
+	args := []string{"ctx context.Context"}
+	for _, p := range op.Params {
+		args = append(args, p.Var+" "+dtoType(p.Type))
+	}
+	if op.Body != "" {
+		args = append(args, "body "+dtoType(op.Body))
+	}
+	results := "error"
+	if op.Response != "" {
+		results = "(" + dtoType(op.Response) + ", error)"
+	}
+	return strings.Join(args, ", "), results
+}
+
+func opTypes(op APIOperation) []string {
+	types := []string{op.Body, op.Response}
+	for _, p := range op.Params {
+		types = append(types, p.Type)
+	}
+	return types
+}
+
+// generatedHeader names the spec an API file was generated from. The file
+// also gets GeneratedHeader, above it, from Generated.
+func generatedHeader(source string) string {
+	return fmt.Sprintf("// Source: %s\n\n", source)
+}
+
+func APITypesTemplate(source string, types []APIType) string {
+	var b strings.Builder
+	var all []string
+	for _, t := range types {
+		all = append(all, t.Alias)
+		for _, f := range t.Fields {
+			all = append(all, f.Type)
+		}
+	}
+	b.WriteString(generatedHeader(source))
+	b.WriteString("package dto\n\n")
+	if strings.Contains(strings.Join(all, " "), "time.Time") {
+		b.WriteString(importBlock(`"time"`) + "\n")
+	}
+
+	for _, t := range types {
+		if t.Doc != "" {
+			if unicode.IsLower(rune(t.Doc[0])) {
+				fmt.Fprintf(&b, "// %s %s\n", t.Name, t.Doc)
+			} else {
+				fmt.Fprintf(&b, "// %s: %s\n", t.Name, t.Doc)
+			}
+		}
+		if t.Alias != "" {
+			fmt.Fprintf(&b, "type %s %s\n\n", t.Name, t.Alias)
+			continue
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", t.Name)
+		for _, f := range t.Fields {
+			tag := f.JSON
+			if !f.Required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", f.Name, f.Type, tag)
+		}
+		b.WriteString("}\n\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// APIServiceTemplate declares the service the API handlers call. It is
+// regenerated from the spec; the implementation lives in api_service.go.
+func APIServiceTemplate(source, module string, ops []APIOperation) string {
+	var methods []string
+	imports := []string{`"context"`, `"errors"`}
+	for _, op := range ops {
+		args, results := apiSignature(op)
+		methods = append(methods, fmt.Sprintf("\t%s(%s) %s", op.Name, args, results))
+		imports = append(imports, apiTypeImports(module, opTypes(op)...)...)
+	}
+
+	return generatedHeader(source) + "package services\n\n" + importBlock(imports...) + fmt.Sprintf(`
+// ErrNotImplemented is returned by operations that have not been implemented
+// yet. Handlers answer it with 501 Not Implemented.
+var ErrNotImplemented = errors.New("not implemented")
+
+type APIService interface {
+%s
+}
+`, strings.Join(methods, "\n"))
+}
+
+// APIServiceImplTemplate starts the user-owned API service implementation.
+// Stubs for new operations are appended by APIServiceStub.
+func APIServiceImplTemplate() string {
+	return `package services
+
+type apiService struct{}
+
+func NewAPIService() APIService {
+	return &apiService{}
+}
+`
+}
+
+// APIServiceStub returns an unimplemented service method and the imports it needs.
+func APIServiceStub(module string, op APIOperation) (string, []string) {
+	args, results := apiSignature(op)
+	body := "\treturn ErrNotImplemented"
+	if op.Response != "" {
+		body = fmt.Sprintf("\tvar res %s\n\treturn res, ErrNotImplemented", dtoType(op.Response))
+	}
+	return fmt.Sprintf("\nfunc (s *apiService) %s(%s) %s {\n%s\n}\n", op.Name, args, results, body),
+		append([]string{`"context"`}, apiTypeImports(module, opTypes(op)...)...)
+}
+
+// apiDialect holds how each framework reads a request and writes a response.
+type apiDialect struct {
+	signature  string
+	ctx        string
+	pathParam  string
+	queryParam string
+	bind       string
+	fail       string
+	ok         string
+	noContent  string
+	imports    []string
+}
+
+var apiDialects = map[string]apiDialect{
+	"fiber": {
+		signature: "(c fiber.Ctx) error", ctx: "c.Context()",
+		pathParam: `c.Params("%s")`, queryParam: `c.Query("%s")`,
+		bind: "c.Bind().Body(&body)",
+		fail: `return c.Status(%s).JSON(fiber.Map{"error": %s})`,
+		ok:   "return c.Status(%s).JSON(%s)", noContent: "return c.SendStatus(%s)",
+		imports: []string{`"github.com/gofiber/fiber/v3"`},
+	},
+	"gin": {
+		signature: "(c *gin.Context)", ctx: "c.Request.Context()",
+		pathParam: `c.Param("%s")`, queryParam: `c.Query("%s")`,
+		bind: "c.ShouldBindJSON(&body)",
+		fail: "c.JSON(%s, gin.H{\"error\": %s})\n\t\treturn",
+		ok:   "c.JSON(%s, %s)", noContent: "c.Status(%s)",
+		imports: []string{`"github.com/gin-gonic/gin"`},
+	},
+	"echo": {
+		signature: "(c echo.Context) error", ctx: "c.Request().Context()",
+		pathParam: `c.Param("%s")`, queryParam: `c.QueryParam("%s")`,
+		bind: "c.Bind(&body)",
+		fail: `return c.JSON(%s, map[string]string{"error": %s})`,
+		ok:   "return c.JSON(%s, %s)", noContent: "return c.NoContent(%s)",
+		imports: []string{`"github.com/labstack/echo/v4"`},
+	},
+	"chi": {
+		signature: "(w http.ResponseWriter, r *http.Request)", ctx: "r.Context()",
+		pathParam: `chi.URLParam(r, "%s")`, queryParam: `r.URL.Query().Get("%s")`,
+		bind: "json.NewDecoder(r.Body).Decode(&body)",
+		fail: "http.Error(w, %[2]s, %[1]s)\n\t\treturn",
+		ok:   "writeJSON(w, %s, %s)", noContent: "w.WriteHeader(%s)",
+		imports: []string{`"github.com/go-chi/chi/v5"`},
+	},
+	"iris": {
+		signature: "(ctx iris.Context)", ctx: "ctx.Request().Context()",
+		pathParam: `ctx.Params().Get("%s")`, queryParam: `ctx.URLParam("%s")`,
+		bind: "ctx.ReadJSON(&body)",
+		fail: "ctx.StopWithJSON(%s, iris.Map{\"error\": %s})\n\t\treturn",
+		ok:   "ctx.StatusCode(%s)\n\tctx.JSON(%s)", noContent: "ctx.StatusCode(%s)",
+		imports: []string{`"github.com/kataras/iris/v12"`},
+	},
+}
+
+var statusNames = map[int]string{
+	200: "http.StatusOK", 201: "http.StatusCreated", 202: "http.StatusAccepted", 204: "http.StatusNoContent",
+}
+
+func statusConst(code int) string {
+	if name, ok := statusNames[code]; ok {
+		return name
+	}
+	return fmt.Sprint(code)
+}
+
+// parseParam returns the statements that read a parameter into its variable.
+func parseParam(d apiDialect, p APIParam) (string, []string) {
+	raw := fmt.Sprintf(d.queryParam, p.Name)
+	if p.In == "path" {
+		raw = fmt.Sprintf(d.pathParam, p.Name)
+	}
+	var conv string
+	switch p.Type {
+	case "string":
+		return fmt.Sprintf("\t%s := %s\n", p.Var, raw), nil
+	case "int":
+		conv = "strconv.Atoi(raw)"
+	case "int32":
+		conv = "strconv.ParseInt(raw, 10, 32)"
+	case "int64":
+		conv = "strconv.ParseInt(raw, 10, 64)"
+	case "float32":
+		conv = "strconv.ParseFloat(raw, 32)"
+	case "float64":
+		conv = "strconv.ParseFloat(raw, 64)"
+	case "bool":
+		conv = "strconv.ParseBool(raw)"
+	case "time.Time":
+		conv = "time.Parse(time.RFC3339, raw)"
+	default:
+		// Parameters of other types are passed through as their zero value.
+		return fmt.Sprintf("\tvar %s %s\n", p.Var, dtoType(p.Type)), nil
+	}
+	imports := []string{`"strconv"`}
+	if p.Type == "time.Time" {
+		imports = []string{`"time"`}
+	}
+	assign := "v"
+	if p.Type == "int32" || p.Type == "float32" {
+		assign = p.Type + "(v)"
+	}
+	return fmt.Sprintf(`	var %[1]s %[2]s
+	if raw := %[3]s; raw != "" {
+		v, err := %[4]s
+		if err != nil {
+			%[5]s
+		}
+		%[1]s = %[6]s
+	}
+`, p.Var, p.Type, raw, conv, fmt.Sprintf(d.fail, "http.StatusBadRequest", fmt.Sprintf("%q", "invalid "+p.Name)), assign), imports
+}
+
+// APIHandlerTemplate starts the user-owned API handler file. Methods for new
+// operations are appended by APIHandlerMethod.
+func APIHandlerTemplate(module string) string {
+	return "package handlers\n\n" + importBlock(quote(module+"/internal/services")) + `
+type APIHandler struct {
+	service services.APIService
+}
+
+func NewAPIHandler(s services.APIService) *APIHandler {
+	return &APIHandler{service: s}
+}
+`
+}
+
+// APIHandlerMethod returns the handler for an operation and the imports it needs.
+func APIHandlerMethod(module, framework string, op APIOperation) (string, []string) {
+	d := apiDialects[framework]
+	imports := append([]string{`"errors"`, `"net/http"`, quote(module + "/internal/services")}, apiTypeImports(module, opTypes(op)...)...)
+	pathParams := false
+	for _, p := range op.Params {
+		pathParams = pathParams || p.In == "path"
+	}
+	// Chi is only referenced through chi.URLParam.
+	if framework != "chi" || pathParams {
+		imports = append(imports, d.imports...)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n// %s handles %s %s.\n", op.Name, op.Method, op.Path)
+	if op.Summary != "" {
+		fmt.Fprintf(&b, "// %s\n", op.Summary)
+	}
+	fmt.Fprintf(&b, "func (h *APIHandler) %s%s {\n", op.Name, d.signature)
+
+	args := []string{d.ctx}
+	for _, p := range op.Params {
+		code, imps := parseParam(d, p)
+		b.WriteString(code)
+		imports = append(imports, imps...)
+		args = append(args, p.Var)
+	}
+	if op.Body != "" {
+		fmt.Fprintf(&b, "\tvar body %s\n\tif err := %s; err != nil {\n\t\t%s\n\t}\n",
+			dtoType(op.Body), d.bind, fmt.Sprintf(d.fail, "http.StatusBadRequest", "err.Error()"))
+		args = append(args, "body")
+		if framework == "chi" {
+			imports = append(imports, `"encoding/json"`)
+		}
+	}
+
+	call := fmt.Sprintf("h.service.%s(%s)", op.Name, strings.Join(args, ", "))
+	if op.Response != "" {
+		fmt.Fprintf(&b, "\tres, err := %s\n", call)
+	} else {
+		fmt.Fprintf(&b, "\terr := %s\n", call)
+	}
+	fmt.Fprintf(&b, "\tif errors.Is(err, services.ErrNotImplemented) {\n\t\t%s\n\t}\n", fmt.Sprintf(d.fail, "http.StatusNotImplemented", "err.Error()"))
+	fmt.Fprintf(&b, "\tif err != nil {\n\t\t%s\n\t}\n", fmt.Sprintf(d.fail, "http.StatusInternalServerError", "err.Error()"))
+	if op.Response != "" {
+		fmt.Fprintf(&b, "\t%s\n", fmt.Sprintf(d.ok, statusConst(op.Status), "res"))
+	} else {
+		fmt.Fprintf(&b, "\t%s\n", fmt.Sprintf(d.noContent, statusConst(op.Status)))
+	}
+	b.WriteString("}\n")
+	return b.String(), imports
+}
+
+// apiRoutePath converts an OpenAPI path template to the framework's syntax.
+func apiRoutePath(framework, path string) string {
+	if framework == "chi" || framework == "iris" {
+		return path
+	}
+	var b strings.Builder
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			seg = ":" + seg[1:len(seg)-1]
+		}
+		b.WriteString(seg + "/")
+	}
+	return strings.TrimSuffix(b.String(), "/")
+}
+
+// APIRegisterCall is the statement added to SetupRoutes for the API routes.
+func APIRegisterCall(framework string) string {
+	if framework == "chi" {
+		return "RegisterAPIRoutes(r)"
+	}
+	return "RegisterAPIRoutes(api)"
+}
+
+func APIRoutesTemplate(source, module, framework string, ops []APIOperation) string {
+	var router, method string
+	switch framework {
+	case "fiber":
+		router, method = "api fiber.Router", "title"
+	case "gin":
+		router, method = "api *gin.RouterGroup", "upper"
+	case "echo":
+		router, method = "api *echo.Group", "upper"
+	case "chi":
+		router, method = "r chi.Router", "title"
+	case "iris":
+		router, method = "api iris.Party", "title"
+	}
+
+	var routes []string
+	for _, op := range ops {
+		name := strings.ToUpper(op.Method)
+		if method == "title" {
+			name = name[:1] + strings.ToLower(name[1:])
+		}
+		if framework == "chi" {
+			routes = append(routes, fmt.Sprintf("\tr.%s(%q, h.%s)", name, "/api/v1"+op.Path, op.Name))
+		} else {
+			routes = append(routes, fmt.Sprintf("\tapi.%s(%q, h.%s)", name, apiRoutePath(framework, op.Path), op.Name))
+		}
+	}
+
+	imports := append([]string{quote(module + "/internal/handlers"), quote(module + "/internal/services")}, apiDialects[framework].imports...)
+	return generatedHeader(source) + "package routes\n\n" + importBlock(imports...) + fmt.Sprintf(`
+func RegisterAPIRoutes(%s) {
+	h := handlers.NewAPIHandler(services.NewAPIService())
+
+%s
+}
+`, router, strings.Join(routes, "\n"))
+}
@@ -0,0 +1,88 @@
+package templates
+
+// DocsTemplate is the docs package of a generated project. It embeds the
+// OpenAPI document goscaf keeps in docs/openapi.json and serves it with
+// Swagger UI at /docs and Redoc at /docs/redoc.
+func DocsTemplate() string {
+	return "// Package docs serves the project's OpenAPI document. openapi.json is\n" +
+		"// rewritten by `goscaf generate`; this file is yours to edit.\n" +
+		`package docs
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+// Handler serves the spec at /docs/openapi.json, Redoc at /docs/redoc and
+// Swagger UI for every other path under /docs.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/openapi.json"):
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(Spec)
+		case strings.HasSuffix(r.URL.Path, "/redoc"):
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(redocPage))
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(swaggerPage))
+		}
+	})
+}
+
+const swaggerPage = ` + "`" + `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/docs/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+` + "`" + `
+
+const redocPage = ` + "`" + `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API docs</title>
+</head>
+<body>
+  <redoc spec-url="/docs/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+` + "`" + `
+`
+}
+
+// DocsRoutes returns the statements SetupRoutes uses to serve the docs
+// package at /docs, and the imports they need beyond the framework's own.
+func DocsRoutes(module, framework string) (stmts, imports []string) {
+	imports = []string{`"` + module + `/docs"`}
+	switch framework {
+	case "fiber":
+		imports = append(imports, `"github.com/gofiber/fiber/v3/middleware/adaptor"`)
+		stmts = []string{`app.Get("/docs*", adaptor.HTTPHandler(docs.Handler()))`}
+	case "gin":
+		stmts = []string{`r.GET("/docs", gin.WrapH(docs.Handler()))`, `r.GET("/docs/*any", gin.WrapH(docs.Handler()))`}
+	case "echo":
+		stmts = []string{`e.GET("/docs*", echo.WrapHandler(docs.Handler()))`}
+	case "chi":
+		stmts = []string{`r.Handle("/docs", docs.Handler())`, `r.Handle("/docs/*", docs.Handler())`}
+	case "iris":
+		stmts = []string{`app.Get("/docs", iris.FromStd(docs.Handler()))`, `app.Get("/docs/{p:path}", iris.FromStd(docs.Handler()))`}
+	}
+	return stmts, imports
+}
@@ -0,0 +1,17 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/samznd/goscaf/internal/regions"
+)
+
+// GeneratedHeader opens every file goscaf may write again. Code between
+// "// goscaf:begin" and "// goscaf:end" lines is preserved when it does.
+const GeneratedHeader = "// Code generated by goscaf; DO NOT EDIT outside custom regions."
+
+// Generated adds the header and a trailing custom region, for declarations
+// of the user's own, to the content of a file goscaf regenerates.
+func Generated(content string) string {
+	return GeneratedHeader + "\n\n" + strings.TrimLeft(content, "\n") + "\n" + regions.Empty("custom", "")
+}
@@ -20,11 +20,19 @@ var InitTemplateCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		projectName, backend, orm := args[0], args[1], args[2]
+		rpc := "none"
+		if len(args) > 3 {
+			rpc = strings.ToLower(args[3])
+		}
+		database := ""
+		if len(args) > 4 {
+			database = strings.ToLower(args[4])
+		}
 
-		repositoryTemplate := RepositoryTemplate(strings.ToLower(orm))
+		repositoryTemplate := RepositoryTemplate(projectName, strings.ToLower(orm), database)
 		serviceTemplate := ServiceTemplate(projectName)
-		handlerTemplate := HandlerGenerator(projectName, strings.ToLower(backend), strings.ToLower(orm))
-		setupRoutesTemplate := SetupRoutesTemplate(projectName, strings.ToLower(backend))
+		handlerTemplate := HandlerGenerator(projectName, strings.ToLower(backend))
+		setupRoutesTemplate := SetupRoutesTemplate(projectName, strings.ToLower(backend), rpc)
 		projectPath := filepath.Join(".", projectName)
 		utils.CreateTemplate("repositories", "repository.go", repositoryTemplate, projectPath)
 		utils.CreateTemplate("services", "service.go", serviceTemplate, projectPath)
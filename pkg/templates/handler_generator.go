@@ -2,21 +2,13 @@ package templates
 
 import "fmt"
 
-func fiberHandler(projectName, orm string) string {
-	ctxLine := ""
-	call := "h.service.GetMessage()"
-	if orm == "ent" {
-		ctxLine = "context \"context\""
-		call = "h.service.GetMessage(context.Background())"
-	}
-
+func fiberHandler(projectName string) string {
 	return fmt.Sprintf(`
 package handlers
 
 import (
 	"%s/internal/services"
 	"github.com/gofiber/fiber/v3"
-	%s
 )
 
 type Handler struct {
@@ -27,25 +19,21 @@ func NewHandler(s services.Service) *Handler {
 	return &Handler{service: s}
 }
 
+func (h *Handler) Service() services.Service {
+	return h.service
+}
+
 func (h *Handler) Get(c fiber.Ctx) error {
-	message, err := %s
+	message, err := h.service.GetMessage()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"message": message})
 }
-`, projectName, ctxLine, call)
+`, projectName)
 }
 
-func ginHandler(projectName, orm string) string {
-	ctxArg := ""
-	call := "h.service.GetMessage()"
-
-	if orm == "ent" {
-		ctxArg = "c.Request.Context()"
-		call = "h.service.GetMessage(" + ctxArg + ")"
-	}
-
+func ginHandler(projectName string) string {
 	return fmt.Sprintf(`
 package handlers
 
@@ -62,23 +50,22 @@ func NewHandler(s services.Service) *Handler {
 	return &Handler{service: s}
 }
 
+func (h *Handler) Service() services.Service {
+	return h.service
+}
+
 func (h *Handler) Get(c *gin.Context) {
-	message, err := %s
+	message, err := h.service.GetMessage()
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(200, gin.H{"message": message})
 }
-`, projectName, call)
+`, projectName)
 }
 
-func echoHandler(projectName, orm string) string {
-	call := "h.service.GetMessage()"
-	if orm == "ent" {
-		call = "h.service.GetMessage(c.Request().Context())"
-	}
-
+func echoHandler(projectName string) string {
 	return fmt.Sprintf(`
 package handlers
 
@@ -96,22 +83,21 @@ func NewHandler(s services.Service) *Handler {
 	return &Handler{service: s}
 }
 
+func (h *Handler) Service() services.Service {
+	return h.service
+}
+
 func (h *Handler) Get(c echo.Context) error {
-	message, err := %s
+	message, err := h.service.GetMessage()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 	return c.JSON(http.StatusOK, map[string]string{"message": message})
 }
-`, projectName, call)
+`, projectName)
 }
 
-func irisHandler(projectName, orm string) string {
-	call := "h.service.GetMessage()"
-	if orm == "ent" {
-		call = "h.service.GetMessage(ctx.Request().Context())"
-	}
-
+func irisHandler(projectName string) string {
 	return fmt.Sprintf(`
 package handlers
 
@@ -128,23 +114,22 @@ func NewHandler(s services.Service) *Handler {
 	return &Handler{service: s}
 }
 
+func (h *Handler) Service() services.Service {
+	return h.service
+}
+
 func (h *Handler) Get(ctx iris.Context) {
-	message, err := %s
+	message, err := h.service.GetMessage()
 	if err != nil {
 		ctx.StopWithStatus(500)
 		return
 	}
 	ctx.JSON(iris.Map{"message": message})
 }
-`, projectName, call)
+`, projectName)
 }
 
-func chiHandler(projectName, orm string) string {
-	call := "h.service.GetMessage()"
-	if orm == "ent" {
-		call = "h.service.GetMessage(r.Context())"
-	}
-
+func chiHandler(projectName string) string {
 	return fmt.Sprintf(`
 package handlers
 
@@ -162,29 +147,33 @@ func NewHandler(s services.Service) *Handler {
 	return &Handler{service: s}
 }
 
+func (h *Handler) Service() services.Service {
+	return h.service
+}
+
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
-	message, err := %s
+	message, err := h.service.GetMessage()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	json.NewEncoder(w).Encode(map[string]string{"message": message})
 }
-`, projectName, call)
+`, projectName)
 }
 
-func HandlerGenerator(projectName, framework, orm string) string {
+func HandlerGenerator(projectName, framework string) string {
 	switch framework {
 	case "fiber":
-		return fiberHandler(projectName, orm)
+		return fiberHandler(projectName)
 	case "gin":
-		return ginHandler(projectName, orm)
+		return ginHandler(projectName)
 	case "echo":
-		return echoHandler(projectName, orm)
+		return echoHandler(projectName)
 	case "chi":
-		return chiHandler(projectName, orm)
+		return chiHandler(projectName)
 	case "iris":
-		return irisHandler(projectName, orm)
+		return irisHandler(projectName)
 	default:
 		return ""
 	}
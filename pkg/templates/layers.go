@@ -2,10 +2,186 @@ package templates
 
 import (
 	"fmt"
+
+	"github.com/samznd/goscaf/internal/regions"
 )
 
-func RepositoryTemplate(orm string) string {
+// RepositoryArg is what main.go passes to the NewRepository that
+// RepositoryTemplate writes.
+func RepositoryArg(orm, database string) string {
+	if database == "mongodb" {
+		return `config.DB.Collection("messages")`
+	}
+	switch orm {
+	case "bun", "sqlx/pgx", "sqlc", "gorm", "xorm", "ent":
+		return "config.DB"
+	}
+	return ""
+}
+
+func RepositoryTemplate(projectName, orm, database string) string {
+	if database == "mongodb" {
+		return `
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type Repository interface {
+	GetMessage() (string, error)
+}
+
+type RepoImpl struct {
+	collection *mongo.Collection
+}
+
+func NewRepository(collection *mongo.Collection) Repository {
+	return &RepoImpl{collection: collection}
+}
+
+func (r *RepoImpl) GetMessage() (string, error) {
+	var result struct {
+		Message string ` + "`bson:\"message\"`" + `
+	}
+	err := r.collection.FindOne(context.Background(), bson.D{}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "data from repository", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.Message, nil
+}
+`
+	}
+
 	switch orm {
+	case "bun":
+		return `
+package repositories
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+type Repository interface {
+	GetMessage() (string, error)
+}
+
+type RepoImpl struct {
+	db *bun.DB
+}
+
+func NewRepository(db *bun.DB) Repository {
+	return &RepoImpl{db: db}
+}
+
+func (r *RepoImpl) GetMessage() (string, error) {
+	var message string
+	if err := r.db.NewRaw("SELECT 'data from repository'").Scan(context.Background(), &message); err != nil {
+		return "", err
+	}
+	return message, nil
+}
+`
+	case "sqlx/pgx":
+		if database == "postgres" || database == "cockroachdb" {
+			return `
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	GetMessage() (string, error)
+}
+
+type RepoImpl struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &RepoImpl{pool: pool}
+}
+
+func (r *RepoImpl) GetMessage() (string, error) {
+	var message string
+	if err := r.pool.QueryRow(context.Background(), "SELECT 'data from repository'").Scan(&message); err != nil {
+		return "", err
+	}
+	return message, nil
+}
+`
+		}
+		return `
+package repositories
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	GetMessage() (string, error)
+}
+
+type RepoImpl struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &RepoImpl{db: db}
+}
+
+func (r *RepoImpl) GetMessage() (string, error) {
+	var message string
+	if err := r.db.Get(&message, "SELECT 'data from repository'"); err != nil {
+		return "", err
+	}
+	return message, nil
+}
+`
+	case "sqlc":
+		return fmt.Sprintf(`
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"%s/internal/db"
+)
+
+type Repository interface {
+	GetMessage() (string, error)
+}
+
+type RepoImpl struct {
+	queries *db.Queries
+}
+
+func NewRepository(conn *sql.DB) Repository {
+	return &RepoImpl{queries: db.New(conn)}
+}
+
+func (r *RepoImpl) GetMessage() (string, error) {
+	message, err := r.queries.GetMessage(context.Background())
+	if errors.Is(err, sql.ErrNoRows) {
+		return "data from repository", nil
+	}
+	return message, err
+}
+`, projectName)
 	case "gorm":
 		return `
 package repositories
@@ -58,24 +234,26 @@ func NewRepository(engine *xorm.Engine) Repository {
 
 func (r *RepoImpl) GetMessage() (string, error) {
 	// Example XORM usage
-	result, err := r.engine.QueryString("SELECT 'data from repository'")
-	if err != nil || len(result) == 0 {
-		return ""
+	result, err := r.engine.QueryString("SELECT 'data from repository' AS message")
+	if err != nil {
+		return "", err
 	}
-	return result[0]["'data from repository'"]
+	if len(result) == 0 {
+		return "", nil
+	}
+	return result[0]["message"], nil
 }
 	`
 	case "ent":
-		return `
+		return fmt.Sprintf(`
 package repositories
 
 import (
-	"entgo.io/ent/dialect"
-	"context"
+	"%s/ent"
 )
 
 type Repository interface {
-	GetMessage(ctx context.Context) string
+	GetMessage() (string, error)
 }
 
 type RepoImpl struct {
@@ -86,11 +264,11 @@ func NewRepository(client *ent.Client) Repository {
 	return &RepoImpl{client: client}
 }
 
-func (r *RepoImpl) GetMessage(ctx context.Context) string {
+func (r *RepoImpl) GetMessage() (string, error) {
 	// You can customize this once your Ent schema is defined
-	return "data from repository (ent)"
-}	
-	`
+	return "data from repository (ent)", nil
+}
+`, projectName)
 	}
 	return `
 package repositories
@@ -276,82 +454,149 @@ func (h *Handler) Get(ctx iris.Context) {
 	}
 }
 
-func SetupRoutesTemplate(projectName, framework string) string {
+// SetupRoutesTemplate returns internal/routes/routes.go.
+func SetupRoutesTemplate(projectName, framework, rpc string) string {
+	rpcImport, rpcMount := "", ""
+	if rpc != "" && rpc != "none" {
+		rpcImport = fmt.Sprintf("\n\t\"%s/internal/rpc\"", projectName)
+		rpcMount = rpcMountTemplate(framework)
+	}
+	custom := regions.Empty("custom routes", "\t")
+
 	switch framework {
 	case "fiber":
-		return fmt.Sprintf(`
+		return Generated(fmt.Sprintf(`
 package routes
 
 import (
+	"%s/docs"
 	"%s/internal/handlers"
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/adaptor"%s
 )
 
 func SetupRoutes(app fiber.Router, h *handlers.Handler) {
+	app.Get("/docs*", adaptor.HTTPHandler(docs.Handler()))
+
 	api := app.Group("/api")
 	api.Get("/message", h.Get)
-}
-`, projectName)
+%s%s}
+`, projectName, projectName, rpcImport, rpcMount, custom))
 	case "gin":
-		return fmt.Sprintf(`
+		return Generated(fmt.Sprintf(`
 package routes
 
 import (
+	"%s/docs"
 	"%s/internal/handlers"
-	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin"%s
 )
 
-func SetupRoutes(api *gin.RouterGroup, h *handlers.Handler) {
+func SetupRoutes(r *gin.Engine, h *handlers.Handler) {
+	r.GET("/docs", gin.WrapH(docs.Handler()))
+	r.GET("/docs/*any", gin.WrapH(docs.Handler()))
+
+	api := r.Group("/api")
 	api.GET("/message", h.Get)
-}
-`, projectName)
+%s%s}
+`, projectName, projectName, rpcImport, rpcMount, custom))
 	case "echo":
-		return fmt.Sprintf(`
+		return Generated(fmt.Sprintf(`
 package routes
 
 import (
+	"%s/docs"
 	"%s/internal/handlers"
-	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4"%s
 )
 
 func SetupRoutes(e *echo.Echo, h *handlers.Handler) {
+	e.GET("/docs*", echo.WrapHandler(docs.Handler()))
+
 	api := e.Group("/api")
 	api.GET("/message", h.Get)
-}
-`, projectName)
+%s%s}
+`, projectName, projectName, rpcImport, rpcMount, custom))
 	case "chi":
-		return fmt.Sprintf(`
+		return Generated(fmt.Sprintf(`
 package routes
 
 import (
+	"%s/docs"
 	"%s/internal/handlers"
 	"github.com/go-chi/chi/v5"
-	"net/http"
+	"net/http"%s
 )
 
 func SetupRoutes(r chi.Router, h *handlers.Handler) {
+	r.Handle("/docs", docs.Handler())
+	r.Handle("/docs/*", docs.Handler())
+
 	r.Route("/api/v1", func(api chi.Router) {
 		api.Get("/message", func(w http.ResponseWriter, r *http.Request) {
 			h.Get(w, r)
 		})
-	})
-}
-`, projectName)
+%s	})
+%s}
+`, projectName, projectName, rpcImport, rpcMount, custom))
 	case "iris":
-		return fmt.Sprintf(`
+		return Generated(fmt.Sprintf(`
 package routes
 
 import (
+	"%s/docs"
 	"%s/internal/handlers"
-	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12"%s
 )
 
 func SetupRoutes(app *iris.Application, h *handlers.Handler) {
+	app.Get("/docs", iris.FromStd(docs.Handler()))
+	app.Get("/docs/{p:path}", iris.FromStd(docs.Handler()))
+
 	api := app.Party("/api")
 	api.Get("/message", h.Get)
-}
-`, projectName)
+%s%s}
+`, projectName, projectName, rpcImport, rpcMount, custom))
 	default:
 		return `// ❌ Unsupported framework`
 	}
 }
+
+// rpcMountTemplate mounts the handlers returned by rpc.Routes under the
+// router group, which it passes as their prefix, using each framework's
+// catch-all syntax.
+func rpcMountTemplate(framework string) string {
+	switch framework {
+	case "fiber":
+		return `
+	for _, rt := range rpc.Routes("/api", h.Service()) {
+		api.All(rt.Path+"*", adaptor.HTTPHandler(rt.Handler))
+	}
+`
+	case "gin":
+		return `
+	for _, rt := range rpc.Routes("/api", h.Service()) {
+		api.Any(rt.Path+"*any", gin.WrapH(rt.Handler))
+	}
+`
+	case "echo":
+		return `
+	for _, rt := range rpc.Routes("/api", h.Service()) {
+		api.Any(rt.Path+"*", echo.WrapHandler(rt.Handler))
+	}
+`
+	case "chi":
+		return `
+		for _, rt := range rpc.Routes("/api/v1", h.Service()) {
+			api.Handle(rt.Path+"*", rt.Handler)
+		}
+`
+	case "iris":
+		return `
+	for _, rt := range rpc.Routes("/api", h.Service()) {
+		api.Any(rt.Path+"{p:path}", iris.FromStd(rt.Handler))
+	}
+`
+	}
+	return ""
+}
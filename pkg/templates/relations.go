@@ -0,0 +1,603 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samznd/goscaf/pkg/utils"
+)
+
+// Relation links a resource to another generated resource. A belongs-to
+// relation is held by the child, which has the foreign key; the parent sees
+// the same link as a has-many relation.
+type Relation struct {
+	Kind   string // "belongs-to" or "has-many"
+	Name   string // the related resource
+	Table  string // the related resource's table
+	FK     Field  // foreign key on the child
+	PK     Field  // primary key of the parent
+	IDType string // the parent's id type in handlers and services
+}
+
+const (
+	BelongsTo = "belongs-to"
+	HasMany   = "has-many"
+)
+
+// Field is the association field the relation adds to the model: the parent
+// for belongs-to, the children for has-many.
+func (rel Relation) Field() string {
+	if rel.Kind == BelongsTo {
+		return rel.Name
+	}
+	return utils.ToPascal(utils.Pluralize(utils.ToSnake(rel.Name)))
+}
+
+// JSON is the association's JSON key, which is also the value of the
+// include query parameter that loads it.
+func (rel Relation) JSON() string { return utils.ToSnake(rel.Field()) }
+
+func (r Resource) relations(kind string) []Relation {
+	var out []Relation
+	for _, rel := range r.Relations {
+		if rel.Kind == kind {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// Includes returns the include values Get accepts, one per relation.
+func (r Resource) Includes() []string {
+	out := make([]string, len(r.Relations))
+	for i, rel := range r.Relations {
+		out[i] = rel.JSON()
+	}
+	return out
+}
+
+// associationFields renders the model fields that hold related records.
+func associationFields(r Resource) string {
+	if len(r.Relations) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n")
+	for _, rel := range r.Relations {
+		typ := "*" + rel.Name
+		if rel.Kind == HasMany {
+			typ = "[]" + rel.Name
+		}
+		var tag string
+		switch {
+		case r.Database == "mongodb":
+			tag = `bson:"-" `
+		case r.ORM == "gorm":
+			tag = fmt.Sprintf(`gorm:"foreignKey:%s;references:%s" `, rel.FK.Name, rel.PK.Name)
+		case r.ORM == "bun" && rel.Kind == BelongsTo:
+			tag = fmt.Sprintf(`bun:"rel:belongs-to,join:%s=%s" `, rel.FK.Column, rel.PK.Column)
+		case r.ORM == "bun":
+			tag = fmt.Sprintf(`bun:"rel:has-many,join:%s=%s" `, rel.PK.Column, rel.FK.Column)
+		case r.ORM == "xorm":
+			tag = `xorm:"-" `
+		case r.ORM == "ent":
+		default:
+			tag = `db:"-" `
+		}
+		fmt.Fprintf(&b, "\t%s %s `%sjson:\"%s,omitempty\"`\n", rel.Field(), typ, tag, rel.JSON())
+	}
+	return b.String()
+}
+
+// relationRepositoryInterface declares the repository methods of the
+// resource's relations.
+func relationRepositoryInterface(r Resource) string {
+	var b strings.Builder
+	for _, rel := range r.Relations {
+		if rel.Kind == BelongsTo {
+			fmt.Fprintf(&b, "\tListBy%[1]s(ctx context.Context, %[2]sID %[3]s) ([]models.%[4]s, error)\n", rel.Name, utils.ToCamel(rel.Name), rel.IDType, r.Name)
+		}
+		fmt.Fprintf(&b, "\tGetWith%s(ctx context.Context, id %s) (*models.%s, error)\n", rel.Field(), r.IDType(), r.Name)
+	}
+	return b.String()
+}
+
+// relationRepositoryImports returns the imports the relation methods add to
+// the repository.
+func relationRepositoryImports(r Resource) []string {
+	if len(r.Relations) == 0 {
+		return nil
+	}
+	switch {
+	case r.Database == "mongodb":
+	case r.ORM == "gorm":
+		return []string{`"gorm.io/gorm/clause"`}
+	case r.ORM == "bun", r.ORM == "xorm":
+		return nil
+	case r.ORM == "ent":
+		return []string{entPackageImport(r)}
+	}
+	if len(r.relations(BelongsTo)) > 0 {
+		return []string{`"errors"`}
+	}
+	return nil
+}
+
+// entPackageImport imports the Ent package of a resource's predicates under
+// an alias that cannot clash with variable names.
+func entPackageImport(r Resource) string {
+	pkg := strings.ToLower(r.Name)
+	return fmt.Sprintf("ent%s %s", pkg, quote(r.Module+"/ent/"+pkg))
+}
+
+// repositoryConn is the receiver expression used to build the repository of
+// a related resource on the same connection.
+func repositoryConn(r Resource, rel Relation) string {
+	if r.Database == "mongodb" {
+		return fmt.Sprintf("r.collection.Database().Collection(%q)", rel.Table)
+	}
+	switch r.ORM {
+	case "xorm":
+		return "r.engine"
+	case "ent":
+		return "r.client"
+	case "sqlx/pgx":
+		if r.Database == "postgres" || r.Database == "cockroachdb" {
+			return "r.pool"
+		}
+	}
+	return "r.db"
+}
+
+// relationRepositoryMethods implements the relation methods. GORM, Bun and
+// Ent preload related records natively and XORM joins the parent; the other
+// data layers load them through the related resource's repository.
+func relationRepositoryMethods(r Resource) string {
+	var b strings.Builder
+	for _, rel := range r.relations(BelongsTo) {
+		b.WriteString(listByMethod(r, rel))
+	}
+	for _, rel := range r.Relations {
+		b.WriteString(getWithMethod(r, rel))
+	}
+	return b.String()
+}
+
+func listByMethod(r Resource, rel Relation) string {
+	param := utils.ToCamel(rel.Name) + "ID"
+	head := fmt.Sprintf("\nfunc (r *%sRepository) ListBy%s(ctx context.Context, %s %s) ([]models.%s, error) {\n",
+		r.Var(), rel.Name, param, rel.IDType, r.Name)
+	list := r.PluralVar()
+	col := rel.FK.Column
+
+	var body string
+	switch {
+	case r.Database == "mongodb":
+		body = fmt.Sprintf(`	oid, err := bson.ObjectIDFromHex(%[1]s)
+	if err != nil {
+		return nil, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{%[2]q: oid})
+	if err != nil {
+		return nil, err
+	}
+	var %[3]s []models.%[4]s
+	err = cursor.All(ctx, &%[3]s)
+	return %[3]s, err
+`, param, col, list, r.Name)
+	case r.ORM == "gorm":
+		body = fmt.Sprintf(`	var %[1]s []models.%[2]s
+	err := r.db.WithContext(ctx).Where("%[3]s = ?", %[4]s).Find(&%[1]s).Error
+	return %[1]s, err
+`, list, r.Name, col, param)
+	case r.ORM == "xorm":
+		body = fmt.Sprintf(`	var %[1]s []models.%[2]s
+	err := r.engine.Context(ctx).Where("%[3]s = ?", %[4]s).Find(&%[1]s)
+	return %[1]s, err
+`, list, r.Name, col, param)
+	case r.ORM == "bun":
+		body = fmt.Sprintf(`	var %[1]s []models.%[2]s
+	err := r.db.NewSelect().Model(&%[1]s).Where("? = ?", bun.Ident("%[3]s"), %[4]s).Scan(ctx)
+	return %[1]s, err
+`, list, r.Name, col, param)
+	case r.ORM == "ent":
+		body = fmt.Sprintf(`	items, err := r.client.%[2]s.Query().Where(ent%[5]s.%[3]s(%[4]s)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	%[1]s := make([]models.%[2]s, 0, len(items))
+	for _, item := range items {
+		%[1]s = append(%[1]s, to%[2]sModel(item))
+	}
+	return %[1]s, nil
+`, list, r.Name, rel.FK.Name, param, strings.ToLower(r.Name))
+	case r.ORM == "sqlx/pgx" && (r.Database == "postgres" || r.Database == "cockroachdb"):
+		body = fmt.Sprintf(`	rows, err := r.pool.Query(ctx, "SELECT "+%[1]sColumns+" FROM %[2]s WHERE %[3]s = $1", %[4]s)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.%[5]s])
+`, r.Var(), r.Table, col, param, r.Name)
+	case r.ORM == "sqlx/pgx":
+		body = fmt.Sprintf(`	var %[1]s []models.%[2]s
+	err := r.db.SelectContext(ctx, &%[1]s, r.db.Rebind("SELECT "+%[3]sColumns+" FROM %[4]s WHERE %[5]s = ?"), %[6]s)
+	return %[1]s, err
+`, list, r.Name, r.Var(), r.Table, col, param)
+	default:
+		scan := make([]string, len(r.Fields))
+		for i, f := range r.Fields {
+			scan[i] = "&" + r.Var() + "." + f.Name
+		}
+		body = fmt.Sprintf(`	rows, err := r.db.QueryContext(ctx, "SELECT "+%[1]sColumns+" FROM %[2]s WHERE %[3]s = %[4]s", %[5]s)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var %[6]s []models.%[7]s
+	for rows.Next() {
+		var %[1]s models.%[7]s
+		if err := rows.Scan(%[8]s); err != nil {
+			return nil, err
+		}
+		%[6]s = append(%[6]s, %[1]s)
+	}
+	return %[6]s, rows.Err()
+`, r.Var(), r.Table, col, placeholder(r.Database, 1), param, list, r.Name, strings.Join(scan, ", "))
+	}
+	return head + body + "}\n"
+}
+
+func getWithMethod(r Resource, rel Relation) string {
+	head := fmt.Sprintf("\nfunc (r *%sRepository) GetWith%s(ctx context.Context, id %s) (*models.%s, error) {\n",
+		r.Var(), rel.Field(), r.IDType(), r.Name)
+	v, pk := r.Var(), r.PK()
+
+	if r.Database != "mongodb" {
+		switch r.ORM {
+		case "gorm":
+			return head + fmt.Sprintf(`	var %[1]s models.%[2]s
+	err := r.db.WithContext(ctx).Preload(%[3]q).First(&%[1]s, "%[4]s = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &%[1]s, nil
+}
+`, v, r.Name, rel.Field(), pk.Column)
+		case "bun":
+			return head + fmt.Sprintf(`	%[1]s := new(models.%[2]s)
+	err := r.db.NewSelect().Model(%[1]s).Relation(%[3]q).Where("?TableAlias.? = ?", bun.Ident("%[4]s"), id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return %[1]s, nil
+}
+`, v, r.Name, rel.Field(), pk.Column)
+		case "ent":
+			load := fmt.Sprintf(`	if item.Edges.%[1]s != nil {
+		related := to%[2]sModel(item.Edges.%[1]s)
+		m.%[1]s = &related
+	}
+`, rel.Field(), rel.Name)
+			if rel.Kind == HasMany {
+				load = fmt.Sprintf(`	for _, related := range item.Edges.%[1]s {
+		m.%[1]s = append(m.%[1]s, to%[2]sModel(related))
+	}
+`, rel.Field(), rel.Name)
+			}
+			return head + fmt.Sprintf(`	item, err := r.client.%[1]s.Query().Where(ent%[2]s.ID(id)).With%[3]s().Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := to%[1]sModel(item)
+%[4]s	return &m, nil
+}
+`, r.Name, strings.ToLower(r.Name), rel.Field(), load)
+		}
+	}
+
+	if r.Database != "mongodb" && r.ORM == "xorm" && rel.Kind == BelongsTo {
+		set := fmt.Sprintf("\t%s.%s = &row.%s\n", v, rel.Field(), rel.Name)
+		if rel.FK.GoType() != rel.FK.Type {
+			set = fmt.Sprintf("\tif %s.%s != nil {\n\t\t%s.%s = &row.%s\n\t}\n", v, rel.FK.Name, v, rel.Field(), rel.Name)
+		}
+		return head + fmt.Sprintf(`	var row struct {
+		models.%[1]s %[2]s
+		%[3]s models.%[3]s %[2]s
+	}
+	has, err := r.engine.Context(ctx).Table(%[4]q).
+		Join("LEFT", %[5]q, "%[5]s.%[6]s = %[4]s.%[7]s").
+		Where("%[4]s.%[8]s = ?", id).
+		Get(&row)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrNotFound
+	}
+	%[9]s := row.%[1]s
+%[10]s	return &%[9]s, nil
+}
+`, r.Name, "`xorm:\"extends\"`", rel.Name, r.Table, rel.Table, rel.PK.Column, rel.FK.Column, pk.Column, v, set)
+	}
+
+	repo := fmt.Sprintf("New%sRepository(%s)", rel.Name, repositoryConn(r, rel))
+	if rel.Kind == HasMany {
+		return head + fmt.Sprintf(`	%[1]s, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	related, err := %[2]s.ListBy%[3]s(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	%[1]s.%[4]s = related
+	return %[1]s, nil
+}
+`, v, repo, r.Name, rel.Field())
+	}
+
+	fk := v + "." + rel.FK.Name
+	guard := ""
+	if rel.FK.GoType() != rel.FK.Type {
+		guard = fmt.Sprintf("\tif %s == nil {\n\t\treturn %s, nil\n\t}\n", fk, v)
+		if r.Database != "mongodb" {
+			fk = "*" + fk
+		}
+	}
+	if r.Database == "mongodb" {
+		fk += ".Hex()"
+	}
+	return head + fmt.Sprintf(`	%[1]s, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+%[2]s	related, err := %[3]s.Get(ctx, %[4]s)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	%[1]s.%[5]s = related
+	return %[1]s, nil
+}
+`, v, guard, repo, fk, rel.Field())
+}
+
+// relationServiceInterface declares the service methods of the resource's
+// relations.
+func relationServiceInterface(r Resource) string {
+	if len(r.Relations) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, rel := range r.relations(BelongsTo) {
+		fmt.Fprintf(&b, "\tListBy%[1]s(ctx context.Context, %[2]sID %[3]s) ([]models.%[4]s, error)\n", rel.Name, utils.ToCamel(rel.Name), rel.IDType, r.Name)
+	}
+	fmt.Fprintf(&b, "\tGetIncluding(ctx context.Context, id %s, include string) (*models.%s, error)\n", r.IDType(), r.Name)
+	return b.String()
+}
+
+func relationServiceMethods(r Resource) string {
+	if len(r.Relations) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, rel := range r.relations(BelongsTo) {
+		param := utils.ToCamel(rel.Name) + "ID"
+		fmt.Fprintf(&b, `
+func (s *%[1]sService) ListBy%[2]s(ctx context.Context, %[3]s %[4]s) ([]models.%[5]s, error) {
+	return s.repo.ListBy%[2]s(ctx, %[3]s)
+}
+`, r.Var(), rel.Name, param, rel.IDType, r.Name)
+	}
+
+	var cases []string
+	for _, rel := range r.Relations {
+		cases = append(cases, fmt.Sprintf("\tcase %q:\n\t\treturn s.repo.GetWith%s(ctx, id)", rel.JSON(), rel.Field()))
+	}
+	includes := make([]string, len(r.Relations))
+	for i, inc := range r.Includes() {
+		includes[i] = fmt.Sprintf("%q", inc)
+	}
+	fmt.Fprintf(&b, `
+// GetIncluding returns the %[3]s with one relation loaded: %[4]s.
+// Any other include value loads none.
+func (s *%[1]sService) GetIncluding(ctx context.Context, id %[2]s, include string) (*models.%[5]s, error) {
+	switch include {
+%[6]s
+	}
+	return s.repo.Get(ctx, id)
+}
+`, r.Var(), r.IDType(), strings.ReplaceAll(utils.ToSnake(r.Name), "_", " "), strings.Join(includes, " or "), r.Name, strings.Join(cases, "\n"))
+	return b.String()
+}
+
+// includeQuery is the framework's expression for the include query parameter.
+func includeQuery(framework string) string {
+	switch framework {
+	case "fiber", "gin":
+		return `c.Query("include")`
+	case "echo":
+		return `c.QueryParam("include")`
+	case "chi":
+		return `r.URL.Query().Get("include")`
+	case "iris":
+		return `ctx.URLParam("include")`
+	}
+	return `""`
+}
+
+// relationHandlerMethods renders the handlers of the nested list routes.
+func relationHandlerMethods(r Resource) string {
+	var b strings.Builder
+	for _, rel := range r.relations(BelongsTo) {
+		param := utils.ToCamel(rel.Name) + "ID"
+		args := []any{r.Name, rel.Name, param, r.PluralVar()}
+		switch r.Framework {
+		case "fiber":
+			fmt.Fprintf(&b, `
+func (h *%[1]sHandler) ListBy%[2]s(c fiber.Ctx) error {
+	%[3]s, err := parse%[2]sID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	%[4]s, err := h.service.ListBy%[2]s(c.Context(), %[3]s)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(%[4]s)
+}
+`, args...)
+		case "gin":
+			fmt.Fprintf(&b, `
+func (h *%[1]sHandler) ListBy%[2]s(c *gin.Context) {
+	%[3]s, err := parse%[2]sID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	%[4]s, err := h.service.ListBy%[2]s(c.Request.Context(), %[3]s)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, %[4]s)
+}
+`, args...)
+		case "echo":
+			fmt.Fprintf(&b, `
+func (h *%[1]sHandler) ListBy%[2]s(c echo.Context) error {
+	%[3]s, err := parse%[2]sID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	%[4]s, err := h.service.ListBy%[2]s(c.Request().Context(), %[3]s)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, %[4]s)
+}
+`, args...)
+		case "chi":
+			fmt.Fprintf(&b, `
+func (h *%[1]sHandler) ListBy%[2]s(w http.ResponseWriter, r *http.Request) {
+	%[3]s, err := parse%[2]sID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	%[4]s, err := h.service.ListBy%[2]s(r.Context(), %[3]s)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, %[4]s)
+}
+`, args...)
+		case "iris":
+			fmt.Fprintf(&b, `
+func (h *%[1]sHandler) ListBy%[2]s(ctx iris.Context) {
+	%[3]s, err := parse%[2]sID(ctx.Params().Get("id"))
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": "invalid id"})
+		return
+	}
+	%[4]s, err := h.service.ListBy%[2]s(ctx.Request().Context(), %[3]s)
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": err.Error()})
+		return
+	}
+	ctx.JSON(%[4]s)
+}
+`, args...)
+		}
+	}
+	return b.String()
+}
+
+// NestedPath is the route listing a resource's records that belong to the
+// parent of rel, e.g. /users/{id}/orders.
+func (r Resource) NestedPath(rel Relation) string {
+	return "/" + utils.ToKebab(rel.Table) + "/{id}" + r.Path()
+}
+
+// nestedRoutes registers the nested list routes in the framework's syntax.
+func nestedRoutes(r Resource) string {
+	var b strings.Builder
+	for _, rel := range r.relations(BelongsTo) {
+		path := r.NestedPath(rel)
+		switch r.Framework {
+		case "fiber":
+			fmt.Fprintf(&b, "\tapi.Get(%q, h.ListBy%s)\n", strings.Replace(path, "{id}", ":id", 1), rel.Name)
+		case "gin", "echo":
+			fmt.Fprintf(&b, "\tapi.GET(%q, h.ListBy%s)\n", strings.Replace(path, "{id}", ":id", 1), rel.Name)
+		case "chi":
+			fmt.Fprintf(&b, "\tr.Get(%q, h.ListBy%s)\n", "/api/v1"+path, rel.Name)
+		case "iris":
+			fmt.Fprintf(&b, "\tapi.Get(%q, h.ListBy%s)\n", path, rel.Name)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\n" + b.String()
+}
+
+// entEdges declares the Ent edges of the resource's relations.
+func entEdges(r Resource) string {
+	if len(r.Relations) == 0 {
+		return ""
+	}
+	var edges []string
+	for _, rel := range r.Relations {
+		if rel.Kind == HasMany {
+			edges = append(edges, fmt.Sprintf("\t\tedge.To(%q, %s.Type),", rel.JSON(), rel.Name))
+			continue
+		}
+		edge := fmt.Sprintf("\t\tedge.From(%q, %s.Type).Ref(%q).Field(%q).Unique()",
+			rel.JSON(), rel.Name, utils.ToSnake(utils.Pluralize(utils.ToSnake(r.Name))), rel.FK.Column)
+		if !rel.FK.Nullable {
+			edge += ".Required()"
+		}
+		edges = append(edges, edge+",")
+	}
+	return fmt.Sprintf("\nfunc (%s) Edges() []ent.Edge {\n\treturn []ent.Edge{\n%s\n\t}\n}\n", r.Name, strings.Join(edges, "\n"))
+}
+
+// ResourceForeignKeyMigration returns the up and down SQL that add a foreign
+// key column to an existing table. The column is nullable since the table
+// may already have rows.
+func ResourceForeignKeyMigration(r Resource, fk Field) (string, string) {
+	fk.Nullable = true
+	def := columnDef(fk, r.Database)
+	index := "idx_" + r.Table + "_" + fk.Column
+
+	var up, down string
+	switch r.Database {
+	case "sqlserver":
+		// The constraint is named so the down migration can drop it first.
+		column := fk
+		column.References = ""
+		up = fmt.Sprintf("ALTER TABLE %s ADD %s, %s;\nCREATE INDEX %s ON %s (%s);\n", r.Table, columnDef(column, r.Database), foreignKeyConstraint(r.Table, fk), index, r.Table, fk.Column)
+		down = fmt.Sprintf("DROP INDEX %s ON %s;\nALTER TABLE %s DROP CONSTRAINT fk_%s_%s;\nALTER TABLE %s DROP COLUMN %s;\n", index, r.Table, r.Table, r.Table, fk.Column, r.Table, fk.Column)
+	case "mysql":
+		// MySQL indexes foreign key columns itself.
+		up = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s, ADD %s;\n", r.Table, def, foreignKeyConstraint(r.Table, fk))
+		down = fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY fk_%s_%s;\nALTER TABLE %s DROP COLUMN %s;\n", r.Table, r.Table, fk.Column, r.Table, fk.Column)
+	default:
+		up = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\nCREATE INDEX IF NOT EXISTS %s ON %s (%s);\n", r.Table, def, index, r.Table, fk.Column)
+		down = fmt.Sprintf("DROP INDEX IF EXISTS %s;\nALTER TABLE %s DROP COLUMN %s;\n", index, r.Table, fk.Column)
+		if r.Database == "sqlite" {
+			down = fmt.Sprintf("DROP INDEX IF EXISTS %s;\n-- SQLite cannot drop a column that is part of a foreign key;\n-- rebuild %s without %s to revert this migration.\n", index, r.Table, fk.Column)
+		}
+	}
+	return up, down
+}
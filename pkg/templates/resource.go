@@ -0,0 +1,1852 @@
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samznd/goscaf/internal/regions"
+	"github.com/samznd/goscaf/pkg/utils"
+)
+
+// Field is a column of a generated resource.
+type Field struct {
+	Name          string
+	Column        string
+	Type          string
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	References    string
+	Unique        bool
+	Index         bool
+	Default       string // unquoted value, e.g. active, 0, true or now
+	Size          int    // maximum length of a string column
+	Validate      string // go-playground/validator rules, e.g. "required,email"
+}
+
+// Resource describes a model and the CRUD layers generated for it.
+type Resource struct {
+	Module    string
+	Name      string
+	Table     string
+	Fields    []Field
+	Framework string
+	Database  string
+	ORM       string
+	Relations []Relation
+}
+
+// GoType returns the field's Go type, using a pointer for nullable scalars.
+func (f Field) GoType() string {
+	if f.Nullable && !f.PrimaryKey && f.Type != "[]byte" && f.Type != "json.RawMessage" {
+		return "*" + f.Type
+	}
+	return f.Type
+}
+
+// PK returns the primary key field.
+func (r Resource) PK() Field {
+	for _, f := range r.Fields {
+		if f.PrimaryKey {
+			return f
+		}
+	}
+	return r.Fields[0]
+}
+
+// IDType is the Go type handlers and services use for the primary key.
+// MongoDB ObjectIDs travel as hex strings and are converted in the repository.
+func (r Resource) IDType() string {
+	if r.Database == "mongodb" {
+		return "string"
+	}
+	return r.PK().Type
+}
+
+// Validated reports whether any field has validation rules, in which case
+// handlers validate request bodies before calling the service.
+func (r Resource) Validated() bool {
+	for _, f := range r.Fields {
+		if f.Validate != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// idFields lists the id types the resource's layers take as parameters, so
+// their imports can be resolved.
+func (r Resource) idFields() []Field {
+	fields := []Field{{Type: r.IDType()}}
+	for _, rel := range r.Relations {
+		fields = append(fields, Field{Type: rel.IDType})
+	}
+	return fields
+}
+
+func (r Resource) Var() string       { return utils.ToCamel(r.Name) }
+func (r Resource) PluralVar() string { return utils.ToCamel(utils.Pluralize(utils.ToSnake(r.Name))) }
+func (r Resource) FileName() string  { return utils.ToSnake(r.Name) }
+func (r Resource) Path() string      { return "/" + utils.ToKebab(r.Table) }
+
+// RegisterFunc is the routes function SetupRoutes calls for this resource.
+func (r Resource) RegisterFunc() string { return "Register" + r.Name + "Routes" }
+
+// RegisterCall is the statement added to SetupRoutes for this resource.
+func (r Resource) RegisterCall() string {
+	if r.Framework == "chi" {
+		return r.RegisterFunc() + "(r)"
+	}
+	return r.RegisterFunc() + "(api)"
+}
+
+// insertFields returns the fields written on insert.
+func (r Resource) insertFields() []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if !f.AutoIncrement {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// updateFields returns the non-key fields written on update.
+func (r Resource) updateFields() []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if !f.PrimaryKey {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func (r Resource) columns() string {
+	cols := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		cols[i] = f.Column
+	}
+	return strings.Join(cols, ", ")
+}
+
+// typeImports returns the imports needed by the field types.
+func typeImports(fields []Field) []string {
+	set := map[string]bool{}
+	for _, f := range fields {
+		switch strings.TrimPrefix(f.Type, "*") {
+		case "time.Time":
+			set[`"time"`] = true
+		case "uuid.UUID":
+			set[`"github.com/google/uuid"`] = true
+		case "decimal.Decimal":
+			set[`"github.com/shopspring/decimal"`] = true
+		case "json.RawMessage":
+			set[`"encoding/json"`] = true
+		case "bson.ObjectID":
+			set[`"go.mongodb.org/mongo-driver/v2/bson"`] = true
+		}
+	}
+	imports := make([]string, 0, len(set))
+	for imp := range set {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// importBlock renders an import declaration with the standard library
+// grouped ahead of third-party packages.
+func importBlock(imports ...string) string {
+	seen := map[string]bool{}
+	var std, other []string
+	for _, imp := range imports {
+		if imp == "" || seen[imp] {
+			continue
+		}
+		seen[imp] = true
+		if first, _, _ := strings.Cut(strings.Trim(imp, `"`), "/"); strings.Contains(first, ".") {
+			other = append(other, "\t"+imp)
+		} else {
+			std = append(std, "\t"+imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	groups := []string{}
+	for _, g := range [][]string{std, other} {
+		if len(g) > 0 {
+			groups = append(groups, strings.Join(g, "\n"))
+		}
+	}
+	if len(groups) == 0 {
+		return ""
+	}
+	return "import (\n" + strings.Join(groups, "\n\n") + "\n)\n"
+}
+
+func quote(path string) string { return `"` + path + `"` }
+
+func ResourceModelTemplate(r Resource) string {
+	var b strings.Builder
+	imports := typeImports(r.Fields)
+	if r.ORM == "bun" {
+		imports = append(imports, `"github.com/uptrace/bun"`)
+	}
+
+	fmt.Fprintf(&b, "package models\n\n%s\n", importBlock(imports...))
+	fmt.Fprintf(&b, "type %s struct {\n", r.Name)
+	if r.ORM == "bun" {
+		fmt.Fprintf(&b, "\tbun.BaseModel `bun:\"table:%s\"`\n\n", r.Table)
+	}
+	for _, f := range r.Fields {
+		comment := ""
+		if f.References != "" {
+			comment = " // references " + f.References
+		}
+		fmt.Fprintf(&b, "\t%s %s `%s`%s\n", f.Name, f.GoType(), fieldTags(r, f), comment)
+	}
+	b.WriteString(associationFields(r))
+	b.WriteString("\n" + regions.Empty("custom fields", "\t"))
+	b.WriteString("}\n")
+
+	if r.ORM == "gorm" || r.ORM == "xorm" {
+		fmt.Fprintf(&b, "\nfunc (%s) TableName() string {\n\treturn %q\n}\n", r.Name, r.Table)
+	}
+	return b.String()
+}
+
+func fieldTags(r Resource, f Field) string {
+	jsonName := f.Column
+	if r.Database == "mongodb" && f.PrimaryKey {
+		jsonName = "id"
+	}
+	jsonTag := fmt.Sprintf(`json:"%s"`, jsonName)
+	if f.Nullable {
+		jsonTag = fmt.Sprintf(`json:"%s,omitempty"`, jsonName)
+	}
+
+	if r.Database == "mongodb" {
+		if f.PrimaryKey {
+			return `bson:"_id,omitempty" ` + jsonTag
+		}
+		return fmt.Sprintf(`bson:"%s" `, f.Column) + jsonTag
+	}
+
+	switch r.ORM {
+	case "gorm":
+		tag := "column:" + f.Column
+		if f.PrimaryKey {
+			tag += ";primaryKey"
+		}
+		if f.AutoIncrement {
+			tag += ";autoIncrement"
+		}
+		if f.Size > 0 {
+			tag += fmt.Sprintf(";size:%d", f.Size)
+		}
+		if f.Unique {
+			tag += ";uniqueIndex"
+		} else if f.Index {
+			tag += ";index"
+		}
+		if f.Default != "" {
+			tag += ";default:" + gormDefault(f)
+		}
+		if !f.Nullable && !f.PrimaryKey {
+			tag += ";not null"
+		}
+		return fmt.Sprintf(`gorm:"%s" `, tag) + jsonTag + validateTag(f)
+	case "xorm":
+		tag := "'" + f.Column + "'"
+		if f.Size > 0 {
+			tag = fmt.Sprintf("varchar(%d) ", f.Size) + tag
+		}
+		if f.PrimaryKey {
+			tag += " pk"
+		}
+		if f.AutoIncrement {
+			tag += " autoincr"
+		}
+		if f.Unique {
+			tag += " unique"
+		} else if f.Index {
+			tag += " index"
+		}
+		if f.Default != "" {
+			tag += " default " + sqlDefault(f, "")
+		}
+		if !f.Nullable && !f.PrimaryKey {
+			tag += " notnull"
+		}
+		return fmt.Sprintf(`xorm:"%s" `, tag) + jsonTag + validateTag(f)
+	case "bun":
+		tag := f.Column
+		if f.PrimaryKey {
+			tag += ",pk"
+		}
+		if f.AutoIncrement {
+			tag += ",autoincrement"
+		}
+		if f.Size > 0 {
+			tag += fmt.Sprintf(",type:varchar(%d)", f.Size)
+		}
+		if f.Unique {
+			tag += ",unique"
+		}
+		if f.Default != "" {
+			tag += ",default:" + sqlDefault(f, "")
+		}
+		if f.Nullable {
+			tag += ",nullzero"
+		} else if !f.PrimaryKey {
+			tag += ",notnull"
+		}
+		return fmt.Sprintf(`bun:"%s" `, tag) + jsonTag + validateTag(f)
+	case "ent":
+		return jsonTag + validateTag(f)
+	default:
+		return fmt.Sprintf(`db:"%s" `, f.Column) + jsonTag + validateTag(f)
+	}
+}
+
+// validateTag returns the validate struct tag for a field. Fields that are
+// not required skip their rules when empty.
+func validateTag(f Field) string {
+	if f.Validate == "" {
+		return ""
+	}
+	rules := f.Validate
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		required = required || rule == "required" || strings.HasPrefix(rule, "required_")
+	}
+	if !required && !strings.HasPrefix(rules, "omitempty") {
+		rules = "omitempty," + rules
+	}
+	return fmt.Sprintf(` validate:"%s"`, rules)
+}
+
+// isNumeric reports whether a field holds a number or a boolean, whose
+// defaults are written without quotes.
+func (f Field) isNumeric() bool {
+	switch f.Type {
+	case "int", "int64", "float64", "decimal.Decimal", "bool":
+		return true
+	}
+	return false
+}
+
+// sqlDefault renders the field's default as a SQL literal for the dialect.
+func sqlDefault(f Field, database string) string {
+	switch {
+	case f.Type == "time.Time" && strings.EqualFold(f.Default, "now"):
+		return "CURRENT_TIMESTAMP"
+	case f.Type == "bool":
+		v := strings.EqualFold(f.Default, "true") || f.Default == "1"
+		if database == "sqlserver" {
+			return map[bool]string{true: "1", false: "0"}[v]
+		}
+		return map[bool]string{true: "TRUE", false: "FALSE"}[v]
+	case f.isNumeric():
+		return f.Default
+	}
+	return "'" + strings.ReplaceAll(f.Default, "'", "''") + "'"
+}
+
+// gormDefault renders the default in GORM's tag syntax, where strings are
+// left unquoted.
+func gormDefault(f Field) string {
+	if f.Type == "time.Time" && strings.EqualFold(f.Default, "now") {
+		return "CURRENT_TIMESTAMP"
+	}
+	if f.Type == "bool" {
+		return strings.ToLower(f.Default)
+	}
+	return f.Default
+}
+
+// ResourceErrorsTemplate is shared by every resource repository.
+func ResourceErrorsTemplate() string {
+	return `package repositories
+
+import "errors"
+
+// ErrNotFound is returned by repositories when no record matches.
+var ErrNotFound = errors.New("record not found")
+`
+}
+
+func repositoryInterface(r Resource) string {
+	return fmt.Sprintf(`type %[1]sRepository interface {
+	List(ctx context.Context) ([]models.%[1]s, error)
+	Get(ctx context.Context, id %[2]s) (*models.%[1]s, error)
+	Create(ctx context.Context, %[3]s *models.%[1]s) error
+	Update(ctx context.Context, id %[2]s, %[3]s *models.%[1]s) error
+	Delete(ctx context.Context, id %[2]s) error
+%[4]s}
+`, r.Name, r.IDType(), r.Var(), relationRepositoryInterface(r))
+}
+
+func ResourceRepositoryTemplate(r Resource) string {
+	return resourceRepository(r) + relationRepositoryMethods(r)
+}
+
+func resourceRepository(r Resource) string {
+	if r.Database == "mongodb" {
+		return mongoResourceRepository(r)
+	}
+	switch r.ORM {
+	case "gorm":
+		return gormResourceRepository(r)
+	case "xorm":
+		return xormResourceRepository(r)
+	case "bun":
+		return bunResourceRepository(r)
+	case "ent":
+		return entResourceRepository(r)
+	case "sqlx/pgx":
+		if r.Database == "postgres" || r.Database == "cockroachdb" {
+			return pgxResourceRepository(r)
+		}
+		return sqlxResourceRepository(r)
+	default:
+		return sqlResourceRepository(r)
+	}
+}
+
+func repositoryHeader(r Resource, imports ...string) string {
+	all := append([]string{`"context"`}, imports...)
+	all = append(all, relationRepositoryImports(r)...)
+	all = append(all, quote(r.Module+"/internal/models"))
+	all = append(all, typeImports(r.idFields())...)
+	return "package repositories\n\n" + importBlock(all...) + "\n" + repositoryInterface(r)
+}
+
+func gormResourceRepository(r Resource) string {
+	pk := r.PK()
+	// Related records are loaded for reading only; writes never cascade.
+	omit := ""
+	if len(r.Relations) > 0 {
+		omit = ".Omit(clause.Associations)"
+	}
+	return repositoryHeader(r, `"errors"`, `"gorm.io/gorm"`) + fmt.Sprintf(`
+type %[2]sRepository struct {
+	db *gorm.DB
+}
+
+func New%[1]sRepository(db *gorm.DB) %[1]sRepository {
+	return &%[2]sRepository{db: db}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	var %[3]s []models.%[1]s
+	err := r.db.WithContext(ctx).Find(&%[3]s).Error
+	return %[3]s, err
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	var %[2]s models.%[1]s
+	err := r.db.WithContext(ctx).First(&%[2]s, "%[5]s = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+	return r.db.WithContext(ctx)%[7]s.Create(%[2]s).Error
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	%[2]s.%[6]s = id
+	return r.db.WithContext(ctx)%[7]s.Save(%[2]s).Error
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	return r.db.WithContext(ctx).Delete(&models.%[1]s{}, "%[5]s = ?", id).Error
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), pk.Column, pk.Name, omit)
+}
+
+func xormResourceRepository(r Resource) string {
+	pk := r.PK()
+	return repositoryHeader(r, `"xorm.io/xorm"`) + fmt.Sprintf(`
+type %[2]sRepository struct {
+	engine *xorm.Engine
+}
+
+func New%[1]sRepository(engine *xorm.Engine) %[1]sRepository {
+	return &%[2]sRepository{engine: engine}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	var %[3]s []models.%[1]s
+	err := r.engine.Context(ctx).Find(&%[3]s)
+	return %[3]s, err
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	var %[2]s models.%[1]s
+	has, err := r.engine.Context(ctx).Where("%[5]s = ?", id).Get(&%[2]s)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrNotFound
+	}
+	return &%[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+	_, err := r.engine.Context(ctx).Insert(%[2]s)
+	return err
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	%[2]s.%[6]s = id
+	_, err := r.engine.Context(ctx).Where("%[5]s = ?", id).AllCols().Update(%[2]s)
+	return err
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	_, err := r.engine.Context(ctx).Where("%[5]s = ?", id).Delete(&models.%[1]s{})
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), pk.Column, pk.Name)
+}
+
+func bunResourceRepository(r Resource) string {
+	pk := r.PK()
+	return repositoryHeader(r, `"database/sql"`, `"errors"`, `"github.com/uptrace/bun"`) + fmt.Sprintf(`
+type %[2]sRepository struct {
+	db *bun.DB
+}
+
+func New%[1]sRepository(db *bun.DB) %[1]sRepository {
+	return &%[2]sRepository{db: db}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	var %[3]s []models.%[1]s
+	err := r.db.NewSelect().Model(&%[3]s).Scan(ctx)
+	return %[3]s, err
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	%[2]s := new(models.%[1]s)
+	err := r.db.NewSelect().Model(%[2]s).Where("? = ?", bun.Ident("%[5]s"), id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return %[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+	_, err := r.db.NewInsert().Model(%[2]s).Exec(ctx)
+	return err
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	%[2]s.%[6]s = id
+	_, err := r.db.NewUpdate().Model(%[2]s).WherePK().Exec(ctx)
+	return err
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	_, err := r.db.NewDelete().Model((*models.%[1]s)(nil)).Where("? = ?", bun.Ident("%[5]s"), id).Exec(ctx)
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), pk.Column, pk.Name)
+}
+
+func sqlxResourceRepository(r Resource) string {
+	pk := r.PK()
+	insert := r.insertFields()
+	cols, named := make([]string, len(insert)), make([]string, len(insert))
+	for i, f := range insert {
+		cols[i], named[i] = f.Column, ":"+f.Column
+	}
+	var sets []string
+	for _, f := range r.updateFields() {
+		sets = append(sets, f.Column+" = :"+f.Column)
+	}
+
+	create := fmt.Sprintf(`	_, err := r.db.NamedExecContext(ctx, "INSERT INTO %s (%s) VALUES (%s)", %s)
+	return err`, r.Table, strings.Join(cols, ", "), strings.Join(named, ", "), r.Var())
+	if pk.AutoIncrement {
+		if r.Database == "sqlserver" {
+			create = fmt.Sprintf(`	rows, err := r.db.NamedQueryContext(ctx, "INSERT INTO %s (%s) OUTPUT INSERTED.%s VALUES (%s)", %s)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&%s.%s)
+	}
+	return rows.Err()`, r.Table, strings.Join(cols, ", "), pk.Column, strings.Join(named, ", "), r.Var(), r.Var(), pk.Name)
+		} else {
+			create = fmt.Sprintf(`	res, err := r.db.NamedExecContext(ctx, "INSERT INTO %s (%s) VALUES (%s)", %s)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	%s.%s = %s(id)
+	return nil`, r.Table, strings.Join(cols, ", "), strings.Join(named, ", "), r.Var(), r.Var(), pk.Name, pk.Type)
+		}
+	}
+
+	return repositoryHeader(r, `"database/sql"`, `"errors"`, `"github.com/jmoiron/sqlx"`) + fmt.Sprintf(`
+const %[2]sColumns = "%[7]s"
+
+type %[2]sRepository struct {
+	db *sqlx.DB
+}
+
+func New%[1]sRepository(db *sqlx.DB) %[1]sRepository {
+	return &%[2]sRepository{db: db}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	var %[3]s []models.%[1]s
+	err := r.db.SelectContext(ctx, &%[3]s, "SELECT "+%[2]sColumns+" FROM %[8]s")
+	return %[3]s, err
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	var %[2]s models.%[1]s
+	err := r.db.GetContext(ctx, &%[2]s, r.db.Rebind("SELECT "+%[2]sColumns+" FROM %[8]s WHERE %[5]s = ?"), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+%[9]s
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	%[2]s.%[6]s = id
+	_, err := r.db.NamedExecContext(ctx, "UPDATE %[8]s SET %[10]s WHERE %[5]s = :%[5]s", %[2]s)
+	return err
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM %[8]s WHERE %[5]s = ?"), id)
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), pk.Column, pk.Name, r.columns(), r.Table, create, strings.Join(sets, ", "))
+}
+
+func pgxResourceRepository(r Resource) string {
+	pk := r.PK()
+	insert := r.insertFields()
+	cols, params, args := make([]string, len(insert)), make([]string, len(insert)), make([]string, len(insert))
+	for i, f := range insert {
+		cols[i], params[i], args[i] = f.Column, fmt.Sprintf("$%d", i+1), r.Var()+"."+f.Name
+	}
+	update := r.updateFields()
+	sets, updateArgs := make([]string, len(update)), make([]string, len(update))
+	for i, f := range update {
+		sets[i], updateArgs[i] = fmt.Sprintf("%s = $%d", f.Column, i+1), r.Var()+"."+f.Name
+	}
+	updateArgs = append(updateArgs, "id")
+
+	create := fmt.Sprintf(`	_, err := r.pool.Exec(ctx, "INSERT INTO %s (%s) VALUES (%s)", %s)
+	return err`, r.Table, strings.Join(cols, ", "), strings.Join(params, ", "), strings.Join(args, ", "))
+	if pk.AutoIncrement {
+		create = fmt.Sprintf(`	return r.pool.QueryRow(ctx, "INSERT INTO %s (%s) VALUES (%s) RETURNING %s", %s).Scan(&%s.%s)`,
+			r.Table, strings.Join(cols, ", "), strings.Join(params, ", "), pk.Column, strings.Join(args, ", "), r.Var(), pk.Name)
+	}
+
+	return repositoryHeader(r, `"errors"`, `"github.com/jackc/pgx/v5"`, `"github.com/jackc/pgx/v5/pgxpool"`) + fmt.Sprintf(`
+const %[2]sColumns = "%[7]s"
+
+type %[2]sRepository struct {
+	pool *pgxpool.Pool
+}
+
+func New%[1]sRepository(pool *pgxpool.Pool) %[1]sRepository {
+	return &%[2]sRepository{pool: pool}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+%[2]sColumns+" FROM %[8]s")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.%[1]s])
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+%[2]sColumns+" FROM %[8]s WHERE %[5]s = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	%[2]s, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.%[1]s])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return %[2]s, err
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+%[9]s
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	%[2]s.%[6]s = id
+	_, err := r.pool.Exec(ctx, "UPDATE %[8]s SET %[10]s WHERE %[5]s = $%[11]d", %[12]s)
+	return err
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM %[8]s WHERE %[5]s = $1", id)
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), pk.Column, pk.Name, r.columns(), r.Table, create,
+		strings.Join(sets, ", "), len(update)+1, strings.Join(updateArgs, ", "))
+}
+
+// placeholder returns the n-th (1-based) bind parameter for the dialect.
+func placeholder(database string, n int) string {
+	switch database {
+	case "postgres", "cockroachdb":
+		return fmt.Sprintf("$%d", n)
+	case "sqlserver":
+		return fmt.Sprintf("@p%d", n)
+	default:
+		return "?"
+	}
+}
+
+func sqlResourceRepository(r Resource) string {
+	pk := r.PK()
+	scan := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		scan[i] = "&" + r.Var() + "." + f.Name
+	}
+	scanArgs := strings.Join(scan, ", ")
+
+	insert := r.insertFields()
+	cols, params, args := make([]string, len(insert)), make([]string, len(insert)), make([]string, len(insert))
+	for i, f := range insert {
+		cols[i], params[i], args[i] = f.Column, placeholder(r.Database, i+1), r.Var()+"."+f.Name
+	}
+	update := r.updateFields()
+	sets, updateArgs := make([]string, len(update)), make([]string, len(update))
+	for i, f := range update {
+		sets[i], updateArgs[i] = f.Column+" = "+placeholder(r.Database, i+1), r.Var()+"."+f.Name
+	}
+	updateArgs = append(updateArgs, "id")
+
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", r.Table, strings.Join(cols, ", "), strings.Join(params, ", "))
+	create := fmt.Sprintf(`	_, err := r.db.ExecContext(ctx, "%s", %s)
+	return err`, insertSQL, strings.Join(args, ", "))
+	if pk.AutoIncrement {
+		switch r.Database {
+		case "postgres", "cockroachdb":
+			create = fmt.Sprintf(`	return r.db.QueryRowContext(ctx, "%s RETURNING %s", %s).Scan(&%s.%s)`,
+				insertSQL, pk.Column, strings.Join(args, ", "), r.Var(), pk.Name)
+		case "sqlserver":
+			create = fmt.Sprintf(`	return r.db.QueryRowContext(ctx, "INSERT INTO %s (%s) OUTPUT INSERTED.%s VALUES (%s)", %s).Scan(&%s.%s)`,
+				r.Table, strings.Join(cols, ", "), pk.Column, strings.Join(params, ", "), strings.Join(args, ", "), r.Var(), pk.Name)
+		default:
+			create = fmt.Sprintf(`	res, err := r.db.ExecContext(ctx, "%s", %s)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	%s.%s = %s(id)
+	return nil`, insertSQL, strings.Join(args, ", "), r.Var(), pk.Name, pk.Type)
+		}
+	}
+
+	return repositoryHeader(r, `"database/sql"`, `"errors"`) + fmt.Sprintf(`
+const %[2]sColumns = "%[7]s"
+
+type %[2]sRepository struct {
+	db *sql.DB
+}
+
+func New%[1]sRepository(db *sql.DB) %[1]sRepository {
+	return &%[2]sRepository{db: db}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+%[2]sColumns+" FROM %[8]s")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var %[3]s []models.%[1]s
+	for rows.Next() {
+		var %[2]s models.%[1]s
+		if err := rows.Scan(%[9]s); err != nil {
+			return nil, err
+		}
+		%[3]s = append(%[3]s, %[2]s)
+	}
+	return %[3]s, rows.Err()
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	var %[2]s models.%[1]s
+	err := r.db.QueryRowContext(ctx, "SELECT "+%[2]sColumns+" FROM %[8]s WHERE %[5]s = %[10]s", id).Scan(%[9]s)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+%[11]s
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	%[2]s.%[6]s = id
+	_, err := r.db.ExecContext(ctx, "UPDATE %[8]s SET %[12]s WHERE %[5]s = %[13]s", %[14]s)
+	return err
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM %[8]s WHERE %[5]s = %[10]s", id)
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), pk.Column, pk.Name, r.columns(), r.Table, scanArgs,
+		placeholder(r.Database, 1), create, strings.Join(sets, ", "), placeholder(r.Database, len(update)+1), strings.Join(updateArgs, ", "))
+}
+
+func mongoResourceRepository(r Resource) string {
+	return repositoryHeader(r, `"errors"`, `"go.mongodb.org/mongo-driver/v2/bson"`, `"go.mongodb.org/mongo-driver/v2/mongo"`) + fmt.Sprintf(`
+type %[2]sRepository struct {
+	collection *mongo.Collection
+}
+
+func New%[1]sRepository(collection *mongo.Collection) %[1]sRepository {
+	return &%[2]sRepository{collection: collection}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	cursor, err := r.collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var %[3]s []models.%[1]s
+	err = cursor.All(ctx, &%[3]s)
+	return %[3]s, err
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id string) (*models.%[1]s, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	var %[2]s models.%[1]s
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&%[2]s)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+	res, err := r.collection.InsertOne(ctx, %[2]s)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		%[2]s.%[4]s = oid
+	}
+	return nil
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id string, %[2]s *models.%[1]s) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	%[2]s.%[4]s = oid
+	res, err := r.collection.ReplaceOne(ctx, bson.M{"_id": oid}, %[2]s)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.PK().Name)
+}
+
+func entResourceRepository(r Resource) string {
+	var toModel, setters []string
+	for _, f := range r.Fields {
+		toModel = append(toModel, fmt.Sprintf("\t\t%s: e.%s,", f.Name, f.Name))
+		if f.PrimaryKey {
+			continue
+		}
+		if f.Nullable && f.GoType() != f.Type {
+			setters = append(setters, fmt.Sprintf("\t\tSetNillable%s(%s.%s).", f.Name, r.Var(), f.Name))
+		} else {
+			setters = append(setters, fmt.Sprintf("\t\tSet%s(%s.%s).", f.Name, r.Var(), f.Name))
+		}
+	}
+	setterChain := strings.Join(setters, "\n")
+
+	return repositoryHeader(r, quote(r.Module+"/ent")) + fmt.Sprintf(`
+type %[2]sRepository struct {
+	client *ent.Client
+}
+
+func New%[1]sRepository(client *ent.Client) %[1]sRepository {
+	return &%[2]sRepository{client: client}
+}
+
+func to%[1]sModel(e *ent.%[1]s) models.%[1]s {
+	return models.%[1]s{
+%[5]s
+	}
+}
+
+func (r *%[2]sRepository) List(ctx context.Context) ([]models.%[1]s, error) {
+	items, err := r.client.%[1]s.Query().All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	%[3]s := make([]models.%[1]s, 0, len(items))
+	for _, item := range items {
+		%[3]s = append(%[3]s, to%[1]sModel(item))
+	}
+	return %[3]s, nil
+}
+
+func (r *%[2]sRepository) Get(ctx context.Context, id %[4]s) (*models.%[1]s, error) {
+	item, err := r.client.%[1]s.Get(ctx, id)
+	if ent.IsNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	%[2]s := to%[1]sModel(item)
+	return &%[2]s, nil
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+	item, err := r.client.%[1]s.Create().
+%[6]s
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	*%[2]s = to%[1]sModel(item)
+	return nil
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, id %[4]s, %[2]s *models.%[1]s) error {
+	item, err := r.client.%[1]s.UpdateOneID(id).
+%[6]s
+		Save(ctx)
+	if ent.IsNotFound(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*%[2]s = to%[1]sModel(item)
+	return nil
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id %[4]s) error {
+	err := r.client.%[1]s.DeleteOneID(id).Exec(ctx)
+	if ent.IsNotFound(err) {
+		return ErrNotFound
+	}
+	return err
+}
+`, r.Name, r.Var(), r.PluralVar(), r.IDType(), strings.Join(toModel, "\n"), setterChain)
+}
+
+// ResourceEntSchemaTemplate declares the Ent schema the repository is built on.
+// Run "go generate ./ent" after adding it.
+func ResourceEntSchemaTemplate(r Resource) string {
+	var fields, indexes []string
+	imports := []string{`"entgo.io/ent"`, `"entgo.io/ent/dialect/entsql"`, `"entgo.io/ent/schema"`, `"entgo.io/ent/schema/field"`}
+	for _, f := range r.Fields {
+		var def string
+		switch f.Type {
+		case "int":
+			def = fmt.Sprintf("field.Int(%q)", f.Column)
+		case "int64":
+			def = fmt.Sprintf("field.Int64(%q)", f.Column)
+		case "float64":
+			def = fmt.Sprintf("field.Float(%q)", f.Column)
+		case "bool":
+			def = fmt.Sprintf("field.Bool(%q)", f.Column)
+		case "time.Time":
+			def = fmt.Sprintf("field.Time(%q)", f.Column)
+		case "[]byte":
+			def = fmt.Sprintf("field.Bytes(%q)", f.Column)
+		case "uuid.UUID":
+			def = fmt.Sprintf("field.UUID(%q, uuid.UUID{})", f.Column)
+			imports = append(imports, `"github.com/google/uuid"`)
+		case "json.RawMessage":
+			def = fmt.Sprintf("field.JSON(%q, json.RawMessage{})", f.Column)
+			imports = append(imports, `"encoding/json"`)
+		case "decimal.Decimal":
+			def = fmt.Sprintf("field.Other(%q, decimal.Decimal{}).SchemaType(map[string]string{dialect.Postgres: \"numeric\", dialect.MySQL: \"decimal(20,6)\", dialect.SQLite: \"numeric\"})", f.Column)
+			imports = append(imports, `"entgo.io/ent/dialect"`, `"github.com/shopspring/decimal"`)
+		default:
+			def = fmt.Sprintf("field.String(%q)", f.Column)
+		}
+		if f.PrimaryKey {
+			if f.Column != "id" {
+				def = strings.Replace(def, fmt.Sprintf("(%q", f.Column), `("id"`, 1) + fmt.Sprintf(".StorageKey(%q)", f.Column)
+			}
+		} else if f.Nullable {
+			def += ".Optional().Nillable()"
+		}
+		if f.Size > 0 && f.Type == "string" {
+			def += fmt.Sprintf(".MaxLen(%d)", f.Size)
+		}
+		if f.Unique {
+			def += ".Unique()"
+		}
+		switch {
+		case f.Default == "":
+		case f.Type == "time.Time" && strings.EqualFold(f.Default, "now"):
+			def += ".Default(time.Now)"
+			imports = append(imports, `"time"`)
+		case f.Type == "string":
+			def += fmt.Sprintf(".Default(%q)", f.Default)
+		case f.Type == "bool":
+			def += fmt.Sprintf(".Default(%s)", strings.ToLower(f.Default))
+		case f.Type == "int" || f.Type == "int64" || f.Type == "float64":
+			def += fmt.Sprintf(".Default(%s)", f.Default)
+		}
+		fields = append(fields, "\t\t"+def+",")
+		if f.Index && !f.Unique {
+			indexes = append(indexes, fmt.Sprintf("\t\tindex.Fields(%q),", f.Column))
+		}
+	}
+	if len(r.Relations) > 0 {
+		imports = append(imports, `"entgo.io/ent/schema/edge"`)
+	}
+	indexMethod := ""
+	if len(indexes) > 0 {
+		imports = append(imports, `"entgo.io/ent/schema/index"`)
+		indexMethod = fmt.Sprintf("\nfunc (%s) Indexes() []ent.Index {\n\treturn []ent.Index{\n%s\n\t}\n}\n", r.Name, strings.Join(indexes, "\n"))
+	}
+	sort.Strings(imports)
+
+	return fmt.Sprintf(`package schema
+
+%s
+type %[2]s struct {
+	ent.Schema
+}
+
+func (%[2]s) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: %[3]q}}
+}
+
+func (%[2]s) Fields() []ent.Field {
+	return []ent.Field{
+%[4]s
+	}
+}
+%[5]s%[6]s`, importBlock(imports...), r.Name, r.Table, strings.Join(fields, "\n"), entEdges(r), indexMethod)
+}
+
+func ResourceServiceTemplate(r Resource) string {
+	imports := append([]string{`"context"`, quote(r.Module + "/internal/models"), quote(r.Module + "/internal/repositories")},
+		typeImports(r.idFields())...)
+	return "package services\n\n" + importBlock(imports...) + fmt.Sprintf(`
+type %[1]sService interface {
+	List(ctx context.Context) ([]models.%[1]s, error)
+	Get(ctx context.Context, id %[3]s) (*models.%[1]s, error)
+	Create(ctx context.Context, %[2]s *models.%[1]s) error
+	Update(ctx context.Context, id %[3]s, %[2]s *models.%[1]s) error
+	Delete(ctx context.Context, id %[3]s) error
+%[4]s}
+
+type %[2]sService struct {
+	repo repositories.%[1]sRepository
+}
+
+func New%[1]sService(repo repositories.%[1]sRepository) %[1]sService {
+	return &%[2]sService{repo: repo}
+}
+
+func (s *%[2]sService) List(ctx context.Context) ([]models.%[1]s, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *%[2]sService) Get(ctx context.Context, id %[3]s) (*models.%[1]s, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *%[2]sService) Create(ctx context.Context, %[2]s *models.%[1]s) error {
+	return s.repo.Create(ctx, %[2]s)
+}
+
+func (s *%[2]sService) Update(ctx context.Context, id %[3]s, %[2]s *models.%[1]s) error {
+	return s.repo.Update(ctx, id, %[2]s)
+}
+
+func (s *%[2]sService) Delete(ctx context.Context, id %[3]s) error {
+	return s.repo.Delete(ctx, id)
+}
+`, r.Name, r.Var(), r.IDType(), relationServiceInterface(r)) + relationServiceMethods(r)
+}
+
+// parseIDFunc converts the raw path parameter into the primary key type.
+func parseIDFunc(r Resource) (string, []string) {
+	body := "\treturn raw, nil"
+	var imports []string
+	switch r.IDType() {
+	case "int64":
+		body = "\treturn strconv.ParseInt(raw, 10, 64)"
+		imports = []string{`"strconv"`}
+	case "int":
+		body = "\treturn strconv.Atoi(raw)"
+		imports = []string{`"strconv"`}
+	case "uuid.UUID":
+		body = "\treturn uuid.Parse(raw)"
+		imports = []string{`"github.com/google/uuid"`}
+	}
+	return fmt.Sprintf("\nfunc parse%sID(raw string) (%s, error) {\n%s\n}\n", r.Name, r.IDType(), body), imports
+}
+
+func ResourceHandlerTemplate(r Resource) string {
+	parseID, parseImports := parseIDFunc(r)
+	validate := validateBlock(r)
+	// With relations, Get loads the one named by ?include=.
+	get, include := "Get", ""
+	if len(r.Relations) > 0 {
+		get, include = "GetIncluding", ", "+includeQuery(r.Framework)
+	}
+	relations := relationHandlerMethods(r)
+	common := []string{`"errors"`, quote(r.Module + "/internal/models"), quote(r.Module + "/internal/repositories"), quote(r.Module + "/internal/services")}
+	header := func(imports ...string) string {
+		all := append(append(common, imports...), parseImports...)
+		return "package handlers\n\n" + importBlock(all...) + fmt.Sprintf(`
+type %[1]sHandler struct {
+	service services.%[1]sService
+}
+
+func New%[1]sHandler(s services.%[1]sService) *%[1]sHandler {
+	return &%[1]sHandler{service: s}
+}
+`, r.Name)
+	}
+
+	switch r.Framework {
+	case "fiber":
+		return header(`"github.com/gofiber/fiber/v3"`) + fmt.Sprintf(`
+func (h *%[1]sHandler) List(c fiber.Ctx) error {
+	%[3]s, err := h.service.List(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(%[3]s)
+}
+
+func (h *%[1]sHandler) Get(c fiber.Ctx) error {
+	id, err := parse%[1]sID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	%[2]s, err := h.service.%[6]s(c.Context(), id%[7]s)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(%[2]s)
+}
+
+func (h *%[1]sHandler) Create(c fiber.Ctx) error {
+	var %[2]s models.%[1]s
+	if err := c.Bind().Body(&%[2]s); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+%[5]s	if err := h.service.Create(c.Context(), &%[2]s); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(%[2]s)
+}
+
+func (h *%[1]sHandler) Update(c fiber.Ctx) error {
+	id, err := parse%[1]sID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	var %[2]s models.%[1]s
+	if err := c.Bind().Body(&%[2]s); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+%[5]s	if err := h.service.Update(c.Context(), id, &%[2]s); errors.Is(err, repositories.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(%[2]s)
+}
+
+func (h *%[1]sHandler) Delete(c fiber.Ctx) error {
+	id, err := parse%[1]sID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	if err := h.service.Delete(c.Context(), id); errors.Is(err, repositories.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+%[4]s`, r.Name, r.Var(), r.PluralVar(), parseID, validate, get, include) + relations
+	case "gin":
+		return header(`"net/http"`, `"github.com/gin-gonic/gin"`) + fmt.Sprintf(`
+func (h *%[1]sHandler) List(c *gin.Context) {
+	%[3]s, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, %[3]s)
+}
+
+func (h *%[1]sHandler) Get(c *gin.Context) {
+	id, err := parse%[1]sID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	%[2]s, err := h.service.%[6]s(c.Request.Context(), id%[7]s)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, %[2]s)
+}
+
+func (h *%[1]sHandler) Create(c *gin.Context) {
+	var %[2]s models.%[1]s
+	if err := c.ShouldBindJSON(&%[2]s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+%[5]s	if err := h.service.Create(c.Request.Context(), &%[2]s); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, %[2]s)
+}
+
+func (h *%[1]sHandler) Update(c *gin.Context) {
+	id, err := parse%[1]sID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var %[2]s models.%[1]s
+	if err := c.ShouldBindJSON(&%[2]s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+%[5]s	if err := h.service.Update(c.Request.Context(), id, &%[2]s); errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, %[2]s)
+}
+
+func (h *%[1]sHandler) Delete(c *gin.Context) {
+	id, err := parse%[1]sID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), id); errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+%[4]s`, r.Name, r.Var(), r.PluralVar(), parseID, validate, get, include) + relations
+	case "echo":
+		return header(`"net/http"`, `"github.com/labstack/echo/v4"`) + fmt.Sprintf(`
+func (h *%[1]sHandler) List(c echo.Context) error {
+	%[3]s, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, %[3]s)
+}
+
+func (h *%[1]sHandler) Get(c echo.Context) error {
+	id, err := parse%[1]sID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	%[2]s, err := h.service.%[6]s(c.Request().Context(), id%[7]s)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, %[2]s)
+}
+
+func (h *%[1]sHandler) Create(c echo.Context) error {
+	var %[2]s models.%[1]s
+	if err := c.Bind(&%[2]s); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+%[5]s	if err := h.service.Create(c.Request().Context(), &%[2]s); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, %[2]s)
+}
+
+func (h *%[1]sHandler) Update(c echo.Context) error {
+	id, err := parse%[1]sID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	var %[2]s models.%[1]s
+	if err := c.Bind(&%[2]s); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+%[5]s	if err := h.service.Update(c.Request().Context(), id, &%[2]s); errors.Is(err, repositories.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, %[2]s)
+}
+
+func (h *%[1]sHandler) Delete(c echo.Context) error {
+	id, err := parse%[1]sID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	if err := h.service.Delete(c.Request().Context(), id); errors.Is(err, repositories.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+%[4]s`, r.Name, r.Var(), r.PluralVar(), parseID, validate, get, include) + relations
+	case "chi":
+		return header(`"encoding/json"`, `"net/http"`, `"github.com/go-chi/chi/v5"`) + fmt.Sprintf(`
+func (h *%[1]sHandler) List(w http.ResponseWriter, r *http.Request) {
+	%[3]s, err := h.service.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, %[3]s)
+}
+
+func (h *%[1]sHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parse%[1]sID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	%[2]s, err := h.service.%[6]s(r.Context(), id%[7]s)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, %[2]s)
+}
+
+func (h *%[1]sHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var %[2]s models.%[1]s
+	if err := json.NewDecoder(r.Body).Decode(&%[2]s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+%[5]s	if err := h.service.Create(r.Context(), &%[2]s); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, %[2]s)
+}
+
+func (h *%[1]sHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parse%[1]sID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var %[2]s models.%[1]s
+	if err := json.NewDecoder(r.Body).Decode(&%[2]s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+%[5]s	if err := h.service.Update(r.Context(), id, &%[2]s); errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, %[2]s)
+}
+
+func (h *%[1]sHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parse%[1]sID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Delete(r.Context(), id); errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+%[4]s`, r.Name, r.Var(), r.PluralVar(), parseID, validate, get, include) + relations
+	case "iris":
+		return header(`"github.com/kataras/iris/v12"`) + fmt.Sprintf(`
+func (h *%[1]sHandler) List(ctx iris.Context) {
+	%[3]s, err := h.service.List(ctx.Request().Context())
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": err.Error()})
+		return
+	}
+	ctx.JSON(%[3]s)
+}
+
+func (h *%[1]sHandler) Get(ctx iris.Context) {
+	id, err := parse%[1]sID(ctx.Params().Get("id"))
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": "invalid id"})
+		return
+	}
+	%[2]s, err := h.service.%[6]s(ctx.Request().Context(), id%[7]s)
+	if errors.Is(err, repositories.ErrNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, iris.Map{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": err.Error()})
+		return
+	}
+	ctx.JSON(%[2]s)
+}
+
+func (h *%[1]sHandler) Create(ctx iris.Context) {
+	var %[2]s models.%[1]s
+	if err := ctx.ReadJSON(&%[2]s); err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": err.Error()})
+		return
+	}
+%[5]s	if err := h.service.Create(ctx.Request().Context(), &%[2]s); err != nil {
+		ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": err.Error()})
+		return
+	}
+	ctx.StatusCode(iris.StatusCreated)
+	ctx.JSON(%[2]s)
+}
+
+func (h *%[1]sHandler) Update(ctx iris.Context) {
+	id, err := parse%[1]sID(ctx.Params().Get("id"))
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": "invalid id"})
+		return
+	}
+	var %[2]s models.%[1]s
+	if err := ctx.ReadJSON(&%[2]s); err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": err.Error()})
+		return
+	}
+%[5]s	if err := h.service.Update(ctx.Request().Context(), id, &%[2]s); errors.Is(err, repositories.ErrNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, iris.Map{"error": err.Error()})
+		return
+	} else if err != nil {
+		ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": err.Error()})
+		return
+	}
+	ctx.JSON(%[2]s)
+}
+
+func (h *%[1]sHandler) Delete(ctx iris.Context) {
+	id, err := parse%[1]sID(ctx.Params().Get("id"))
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": "invalid id"})
+		return
+	}
+	if err := h.service.Delete(ctx.Request().Context(), id); errors.Is(err, repositories.ErrNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, iris.Map{"error": err.Error()})
+		return
+	} else if err != nil {
+		ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": err.Error()})
+		return
+	}
+	ctx.StatusCode(iris.StatusNoContent)
+}
+%[4]s`, r.Name, r.Var(), r.PluralVar(), parseID, validate, get, include) + relations
+	}
+	return ""
+}
+
+// validateBlock is the check handlers run on a decoded request body when the
+// resource has validation rules.
+func validateBlock(r Resource) string {
+	if !r.Validated() {
+		return ""
+	}
+	var fail string
+	switch r.Framework {
+	case "fiber":
+		fail = `return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})`
+	case "gin":
+		fail = "c.JSON(http.StatusBadRequest, gin.H{\"error\": err.Error()})\n\t\treturn"
+	case "echo":
+		fail = `return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})`
+	case "chi":
+		fail = "http.Error(w, err.Error(), http.StatusBadRequest)\n\t\treturn"
+	case "iris":
+		fail = `ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": err.Error()})` + "\n\t\treturn"
+	}
+	return fmt.Sprintf("\tif err := validate.Struct(&%s); err != nil {\n\t\t%s\n\t}\n", r.Var(), fail)
+}
+
+// ResourceValidatorTemplate holds the validator shared by resource handlers.
+func ResourceValidatorTemplate() string {
+	return `package handlers
+
+import "github.com/go-playground/validator/v10"
+
+// validate checks request bodies against the validate tags of the models.
+var validate = validator.New(validator.WithRequiredStructEnabled())
+`
+}
+
+// ResourceJSONHelperTemplate holds the response helper shared by net/http (Chi) handlers.
+func ResourceJSONHelperTemplate() string {
+	return `package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+`
+}
+
+func ResourceRoutesTemplate(r Resource) string {
+	repoArg := "config.DB"
+	if r.Database == "mongodb" {
+		repoArg = fmt.Sprintf("config.DB.Collection(%q)", r.Table)
+	}
+	imports := []string{
+		quote(r.Module + "/config"), quote(r.Module + "/internal/handlers"),
+		quote(r.Module + "/internal/repositories"), quote(r.Module + "/internal/services"),
+	}
+	wiring := fmt.Sprintf(`	repo := repositories.New%[1]sRepository(%[2]s)
+	h := handlers.New%[1]sHandler(services.New%[1]sService(repo))
+`, r.Name, repoArg)
+
+	switch r.Framework {
+	case "fiber":
+		return "package routes\n\n" + importBlock(append(imports, `"github.com/gofiber/fiber/v3"`)...) + fmt.Sprintf(`
+func %[1]s(api fiber.Router) {
+%[2]s
+	group := api.Group(%[3]q)
+	group.Get("/", h.List)
+	group.Post("/", h.Create)
+	group.Get("/:id", h.Get)
+	group.Put("/:id", h.Update)
+	group.Delete("/:id", h.Delete)
+%[4]s
+%[5]s}
+`, r.RegisterFunc(), wiring, r.Path(), nestedRoutes(r), regions.Empty("custom routes", "\t"))
+	case "gin":
+		return "package routes\n\n" + importBlock(append(imports, `"github.com/gin-gonic/gin"`)...) + fmt.Sprintf(`
+func %[1]s(api *gin.RouterGroup) {
+%[2]s
+	group := api.Group(%[3]q)
+	group.GET("", h.List)
+	group.POST("", h.Create)
+	group.GET("/:id", h.Get)
+	group.PUT("/:id", h.Update)
+	group.DELETE("/:id", h.Delete)
+%[4]s
+%[5]s}
+`, r.RegisterFunc(), wiring, r.Path(), nestedRoutes(r), regions.Empty("custom routes", "\t"))
+	case "echo":
+		return "package routes\n\n" + importBlock(append(imports, `"github.com/labstack/echo/v4"`)...) + fmt.Sprintf(`
+func %[1]s(api *echo.Group) {
+%[2]s
+	group := api.Group(%[3]q)
+	group.GET("", h.List)
+	group.POST("", h.Create)
+	group.GET("/:id", h.Get)
+	group.PUT("/:id", h.Update)
+	group.DELETE("/:id", h.Delete)
+%[4]s
+%[5]s}
+`, r.RegisterFunc(), wiring, r.Path(), nestedRoutes(r), regions.Empty("custom routes", "\t"))
+	case "chi":
+		return "package routes\n\n" + importBlock(append(imports, `"github.com/go-chi/chi/v5"`)...) + fmt.Sprintf(`
+func %[1]s(r chi.Router) {
+%[2]s
+	r.Route("/api/v1%[3]s", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.Get)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+%[4]s
+%[5]s}
+`, r.RegisterFunc(), wiring, r.Path(), nestedRoutes(r), regions.Empty("custom routes", "\t"))
+	case "iris":
+		return "package routes\n\n" + importBlock(append(imports, `"github.com/kataras/iris/v12"`)...) + fmt.Sprintf(`
+func %[1]s(api iris.Party) {
+%[2]s
+	group := api.Party(%[3]q)
+	group.Get("/", h.List)
+	group.Post("/", h.Create)
+	group.Get("/{id}", h.Get)
+	group.Put("/{id}", h.Update)
+	group.Delete("/{id}", h.Delete)
+%[4]s
+%[5]s}
+`, r.RegisterFunc(), wiring, r.Path(), nestedRoutes(r), regions.Empty("custom routes", "\t"))
+	}
+	return ""
+}
+
+// sqlType maps a Go field type to a column type for the dialect.
+func sqlType(f Field, database string) string {
+	pg := database == "postgres" || database == "cockroachdb"
+	switch f.Type {
+	case "int":
+		if database == "mysql" || database == "sqlserver" {
+			return "INT"
+		}
+		return "INTEGER"
+	case "int64":
+		if database == "sqlite" {
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case "float64":
+		switch {
+		case pg:
+			return "DOUBLE PRECISION"
+		case database == "mysql":
+			return "DOUBLE"
+		case database == "sqlserver":
+			return "FLOAT"
+		}
+		return "REAL"
+	case "bool":
+		if database == "sqlserver" {
+			return "BIT"
+		}
+		return "BOOLEAN"
+	case "time.Time":
+		switch {
+		case pg:
+			return "TIMESTAMPTZ"
+		case database == "sqlserver":
+			return "DATETIME2"
+		}
+		return "DATETIME"
+	case "[]byte":
+		switch {
+		case pg:
+			return "BYTEA"
+		case database == "sqlserver":
+			return "VARBINARY(MAX)"
+		}
+		return "BLOB"
+	case "uuid.UUID":
+		switch {
+		case pg:
+			return "UUID"
+		case database == "sqlserver":
+			return "UNIQUEIDENTIFIER"
+		case database == "mysql":
+			return "CHAR(36)"
+		}
+		return "TEXT"
+	case "json.RawMessage":
+		switch {
+		case pg:
+			return "JSONB"
+		case database == "mysql":
+			return "JSON"
+		case database == "sqlserver":
+			return "NVARCHAR(MAX)"
+		}
+		return "TEXT"
+	case "decimal.Decimal":
+		if database == "sqlite" {
+			return "NUMERIC"
+		}
+		return "DECIMAL(20,6)"
+	default:
+		switch {
+		case f.Size > 0 && database == "sqlserver":
+			return fmt.Sprintf("NVARCHAR(%d)", f.Size)
+		case f.Size > 0:
+			return fmt.Sprintf("VARCHAR(%d)", f.Size)
+		case database == "mysql":
+			return "VARCHAR(255)"
+		case database == "sqlserver":
+			return "NVARCHAR(255)"
+		}
+		return "TEXT"
+	}
+}
+
+// columnDef renders a single column of CREATE TABLE for the dialect.
+func columnDef(f Field, database string) string {
+	if f.PrimaryKey && f.AutoIncrement {
+		switch database {
+		case "mysql":
+			return fmt.Sprintf("%s %s AUTO_INCREMENT PRIMARY KEY", f.Column, sqlType(f, database))
+		case "sqlite":
+			return f.Column + " INTEGER PRIMARY KEY AUTOINCREMENT"
+		case "sqlserver":
+			return fmt.Sprintf("%s %s IDENTITY(1,1) PRIMARY KEY", f.Column, sqlType(f, database))
+		default:
+			if f.Type == "int" {
+				return f.Column + " SERIAL PRIMARY KEY"
+			}
+			return f.Column + " BIGSERIAL PRIMARY KEY"
+		}
+	}
+
+	def := f.Column + " " + sqlType(f, database)
+	if f.PrimaryKey {
+		def += " PRIMARY KEY"
+	} else if !f.Nullable {
+		def += " NOT NULL"
+	}
+	if f.Unique {
+		def += " UNIQUE"
+	}
+	if f.Default != "" {
+		def += " DEFAULT " + sqlDefault(f, database)
+	}
+	// MySQL parses but ignores inline references; its foreign keys are
+	// declared as table constraints by foreignKeyConstraint.
+	if f.References != "" && database != "mysql" {
+		table, column, _ := strings.Cut(f.References, ".")
+		def += fmt.Sprintf(" REFERENCES %s(%s)", table, column)
+	}
+	return def
+}
+
+// foreignKeyConstraint declares a foreign key with a predictable name,
+// so a down migration can drop it.
+func foreignKeyConstraint(table string, f Field) string {
+	refTable, refColumn, _ := strings.Cut(f.References, ".")
+	return fmt.Sprintf("CONSTRAINT fk_%s_%s FOREIGN KEY (%s) REFERENCES %s(%s)", table, f.Column, f.Column, refTable, refColumn)
+}
+
+// ResourceMigrationTemplate returns the up and down SQL that create and drop
+// the resource's table.
+func ResourceMigrationTemplate(r Resource) (string, string) {
+	var defs, indexes []string
+	for _, f := range r.Fields {
+		defs = append(defs, "    "+columnDef(f, r.Database))
+	}
+	for _, f := range r.Fields {
+		if f.References != "" && r.Database == "mysql" {
+			defs = append(defs, "    "+foreignKeyConstraint(r.Table, f))
+		}
+	}
+	for _, f := range r.Fields {
+		if !f.Index || f.Unique {
+			continue
+		}
+		name := "idx_" + r.Table + "_" + f.Column
+		// MySQL and SQL Server have no CREATE INDEX IF NOT EXISTS, so their
+		// indexes are declared with the table.
+		if r.Database == "mysql" || r.Database == "sqlserver" {
+			defs = append(defs, fmt.Sprintf("    INDEX %s (%s)", name, f.Column))
+		} else {
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s);\n", name, r.Table, f.Column))
+		}
+	}
+	body := strings.Join(defs, ",\n")
+
+	up := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n);\n", r.Table, body)
+	if r.Database == "sqlserver" {
+		up = fmt.Sprintf("IF OBJECT_ID('%s', 'U') IS NULL\nCREATE TABLE %s (\n%s\n);\n", r.Table, r.Table, body)
+	}
+	up += strings.Join(indexes, "")
+	return up, fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", r.Table)
+}
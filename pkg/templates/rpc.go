@@ -0,0 +1,228 @@
+package templates
+
+import "fmt"
+
+func ProtoTemplate(projectName, rpc string) string {
+	annotationsImport := ""
+	httpRule := ";"
+	if rpc == "grpc-gateway" {
+		annotationsImport = "\nimport \"google/api/annotations.proto\";\n"
+		httpRule = ` {
+    option (google.api.http) = {
+      get: "/v1/message"
+    };
+  }`
+	}
+
+	return fmt.Sprintf(`syntax = "proto3";
+
+package message.v1;
+%s
+option go_package = "%s/gen/message/v1;messagev1";
+
+service MessageService {
+  rpc GetMessage(GetMessageRequest) returns (GetMessageResponse)%s
+}
+
+message GetMessageRequest {}
+
+message GetMessageResponse {
+  string message = 1;
+}
+`, annotationsImport, projectName, httpRule)
+}
+
+func BufTemplate(rpc string) string {
+	deps := ""
+	if rpc == "grpc-gateway" {
+		deps = `
+deps:
+  - buf.build/googleapis/googleapis`
+	}
+
+	return fmt.Sprintf(`version: v2
+modules:
+  - path: proto%s
+lint:
+  use:
+    - STANDARD
+breaking:
+  use:
+    - FILE
+`, deps)
+}
+
+func BufGenTemplate(rpc string) string {
+	switch rpc {
+	case "connect-rpc":
+		return `version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go
+    out: gen
+    opt: paths=source_relative
+  - remote: buf.build/connectrpc/go
+    out: gen
+    opt: paths=source_relative
+`
+	case "grpc-gateway":
+		return `version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go
+    out: gen
+    opt: paths=source_relative
+  - remote: buf.build/grpc/go
+    out: gen
+    opt: paths=source_relative
+  - remote: buf.build/grpc-ecosystem/gateway
+    out: gen
+    opt: paths=source_relative
+`
+	}
+	return ""
+}
+
+// rpcMountable is the helper of both RPC servers that serves their
+// handlers under a router group.
+const rpcMountable = `
+// mountable strips the prefix of the router group a handler is mounted
+// under, so it sees the paths it was generated for.
+func mountable(prefix string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, prefix+"/") {
+			r = r.Clone(r.Context())
+			r.URL.Path = strings.TrimPrefix(r.URL.Path, prefix)
+			r.URL.RawPath = ""
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+`
+
+func RPCServerTemplate(projectName, rpc string) string {
+	switch rpc {
+	case "connect-rpc":
+		return fmt.Sprintf(`
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	messagev1 "%s/gen/message/v1"
+	"%s/gen/message/v1/messagev1connect"
+	"%s/internal/services"
+)
+
+// Route is an HTTP path prefix served by the RPC layer.
+type Route struct {
+	Path    string
+	Handler http.Handler
+}
+
+type Server struct {
+	service services.Service
+}
+
+func NewServer(s services.Service) *Server {
+	return &Server{service: s}
+}
+
+func (s *Server) GetMessage(ctx context.Context, req *connect.Request[messagev1.GetMessageRequest]) (*connect.Response[messagev1.GetMessageResponse], error) {
+	message, err := s.service.GetMessage()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&messagev1.GetMessageResponse{Message: message}), nil
+}
+
+// Routes returns the handlers to mount on the HTTP router group at prefix.
+// Connect handlers speak the Connect, gRPC and gRPC-Web protocols on the
+// same path, so JSON clients, browsers and gRPC clients all share one proto
+// definition. Native gRPC clients need HTTP/2 (h2c) between them and the
+// server.
+func Routes(prefix string, s services.Service) []Route {
+	path, handler := messagev1connect.NewMessageServiceHandler(NewServer(s))
+	return []Route{{Path: path, Handler: mountable(prefix, handler)}}
+}
+
+%s`, projectName, projectName, projectName, rpcMountable)
+	case "grpc-gateway":
+		return fmt.Sprintf(`
+package rpc
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	messagev1 "%s/gen/message/v1"
+	"%s/internal/services"
+)
+
+// Route is an HTTP path prefix served by the RPC layer.
+type Route struct {
+	Path    string
+	Handler http.Handler
+}
+
+type Server struct {
+	messagev1.UnimplementedMessageServiceServer
+	service services.Service
+}
+
+func NewServer(s services.Service) *Server {
+	return &Server{service: s}
+}
+
+func (s *Server) GetMessage(ctx context.Context, req *messagev1.GetMessageRequest) (*messagev1.GetMessageResponse, error) {
+	message, err := s.service.GetMessage()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &messagev1.GetMessageResponse{Message: message}, nil
+}
+
+// Routes returns the handlers to mount on the HTTP router group at prefix.
+// The gRPC service path serves native gRPC and gRPC-Web, and the gateway
+// reverse-proxy mux serves the JSON routes declared with google.api.http
+// annotations. Native gRPC clients need HTTP/2 (h2c) between them and the
+// server.
+func Routes(prefix string, s services.Service) []Route {
+	srv := NewServer(s)
+
+	grpcServer := grpc.NewServer()
+	messagev1.RegisterMessageServiceServer(grpcServer, srv)
+	grpcWeb := grpcweb.WrapServer(grpcServer)
+
+	mux := runtime.NewServeMux()
+	if err := messagev1.RegisterMessageServiceHandlerServer(context.Background(), mux, srv); err != nil {
+		log.Fatalf("❌ Failed to register gateway handlers: %%v", err)
+	}
+
+	grpcPath := "/" + messagev1.MessageService_ServiceDesc.ServiceName + "/"
+	grpcHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if grpcWeb.IsGrpcWebRequest(r) || grpcWeb.IsAcceptableGrpcCorsRequest(r) {
+			grpcWeb.ServeHTTP(w, r)
+			return
+		}
+		grpcServer.ServeHTTP(w, r)
+	})
+
+	return []Route{
+		{Path: grpcPath, Handler: mountable(prefix, grpcHandler)},
+		{Path: "/v1/", Handler: mountable(prefix, mux)},
+	}
+}
+
+%s`, projectName, projectName, rpcMountable)
+	}
+	return ""
+}
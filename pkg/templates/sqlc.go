@@ -0,0 +1,60 @@
+package templates
+
+import "fmt"
+
+func sqlcEngine(database string) string {
+	switch database {
+	case "mysql":
+		return "mysql"
+	case "sqlite":
+		return "sqlite"
+	default:
+		return "postgresql"
+	}
+}
+
+func SqlcConfigTemplate(database string) string {
+	return fmt.Sprintf(`version: "2"
+sql:
+  - engine: "%s"
+    queries: "db/queries"
+    schema: "db/schema"
+    gen:
+      go:
+        package: "db"
+        out: "internal/db"
+`, sqlcEngine(database))
+}
+
+func SqlcSchemaTemplate(database string) string {
+	id := "id BIGSERIAL PRIMARY KEY"
+	switch database {
+	case "mysql":
+		id = "id BIGINT AUTO_INCREMENT PRIMARY KEY"
+	case "sqlite":
+		id = "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+
+	return fmt.Sprintf(`CREATE TABLE messages (
+    %s,
+    message TEXT NOT NULL
+);
+`, id)
+}
+
+func SqlcQueriesTemplate() string {
+	return `-- name: GetMessage :one
+SELECT message FROM messages
+ORDER BY id
+LIMIT 1;
+`
+}
+
+func SqlcGenerateTemplate() string {
+	return `// Package db holds the code sqlc generates from db/queries and db/schema.
+// Run "go generate ./..." after changing either of them.
+package db
+
+//go:generate go run github.com/sqlc-dev/sqlc/cmd/sqlc@v1.31.1 generate -f ../../sqlc.yaml
+`
+}
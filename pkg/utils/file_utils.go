@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 )
 
+// written lists the files created by CreateFile, in creation order.
+var written []string
+
 // CreateFile writes content into a file at the given path
 func CreateFile(filePath, content string) error {
 	// Create the file
@@ -21,10 +24,18 @@ func CreateFile(filePath, content string) error {
 		return err
 	}
 
+	written = append(written, filePath)
 	fmt.Println("✅ Created file:", filePath)
 	return nil
 }
 
+// WrittenFiles returns the files created since the last call and resets the list.
+func WrittenFiles() []string {
+	files := written
+	written = nil
+	return files
+}
+
 func CreateTemplate(dir, filename, content, projectPath string) {
 	fullPath := filepath.Join(projectPath, "internal", dir, filename)
 	if err := CreateFile(fullPath, content); err != nil {
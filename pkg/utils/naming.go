@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are kept upper-case in Go identifiers, as golint expects.
+var commonInitialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "API": true, "UUID": true,
+	"HTTP": true, "JSON": true, "SQL": true, "IP": true, "UID": true,
+}
+
+// words splits an identifier written in snake_case, kebab-case, spaces or
+// camelCase into lower-case words.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 &&
+			(unicode.IsLower(cur[len(cur)-1]) || unicode.IsDigit(cur[len(cur)-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+// ToPascal converts an identifier to an exported Go name, e.g. "user_id" to "UserID".
+func ToPascal(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		if up := strings.ToUpper(w); commonInitialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// ToCamel converts an identifier to an unexported Go name, e.g. "OrderItem" to "orderItem".
+func ToCamel(s string) string {
+	ws := words(s)
+	if len(ws) == 0 {
+		return ""
+	}
+	return ws[0] + ToPascal(strings.Join(ws[1:], "_"))
+}
+
+// ToSnake converts an identifier to snake_case, e.g. "OrderItem" to "order_item".
+func ToSnake(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// ToKebab converts an identifier to kebab-case, e.g. "OrderItem" to "order-item".
+func ToKebab(s string) string {
+	return strings.Join(words(s), "-")
+}
+
+// Pluralize returns the English plural of a lower-case word using the
+// common suffix rules.
+func Pluralize(s string) string {
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
+
+// Singularize reverses Pluralize for the common suffix rules.
+func Singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "zes"),
+		strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"), strings.HasSuffix(s, "us"):
+		return s
+	case strings.HasSuffix(s, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}